@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/s3"
+	"deploy/internal/store"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	artifactProject string
+	pruneKeep       int
+	pruneKeepTagged bool
+	pruneDryRun     bool
+	untag           bool
+)
+
+// artifactsCmd 构建产物仓库命令
+var artifactsCmd = &cobra.Command{
+	Use:   "artifacts",
+	Short: "管理本地构建产物仓库",
+	Long: `管理本地构建产物仓库 (默认为 ~/.deploy/artifacts)。
+
+每次构建成功后，构建产物、构建清单和校验文件会保存到
+<仓库>/<项目>/<版本>/，之后可以按版本号引用，无需重新构建。
+
+示例：
+  deploy artifacts list                    # 列出当前项目的所有版本
+  deploy artifacts show 1.0.0              # 显示版本详情
+  deploy artifacts path latest             # 输出最新版本的构建产物路径
+  deploy artifacts tag 1.0.0 release       # 为版本添加标签
+  deploy artifacts prune --keep=5          # 只保留最新的 5 个版本和带标签的版本
+  deploy artifacts push 1.0.0              # 上传到远程仓库 (artifact.store.s3)
+  deploy artifacts pull latest             # 从远程仓库下载最新版本到本地仓库`,
+}
+
+// artifactsListCmd 列出版本
+var artifactsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出构建产物",
+	RunE:  runArtifactsList,
+}
+
+// artifactsShowCmd 显示版本详情
+var artifactsShowCmd = &cobra.Command{
+	Use:   "show <版本号>",
+	Short: "显示构建产物详情",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runArtifactsShow,
+}
+
+// artifactsPathCmd 输出构建产物路径
+var artifactsPathCmd = &cobra.Command{
+	Use:   "path <版本号>",
+	Short: "输出构建产物路径",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runArtifactsPath,
+}
+
+// artifactsTagCmd 管理标签
+var artifactsTagCmd = &cobra.Command{
+	Use:   "tag <版本号> <标签>",
+	Short: "为构建产物添加或移除标签",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runArtifactsTag,
+}
+
+// artifactsPushCmd 上传到远程仓库
+var artifactsPushCmd = &cobra.Command{
+	Use:   "push <版本号>",
+	Short: "上传构建产物到远程仓库",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runArtifactsPush,
+}
+
+// artifactsPullCmd 从远程仓库下载
+var artifactsPullCmd = &cobra.Command{
+	Use:   "pull <版本号>",
+	Short: "从远程仓库下载构建产物到本地仓库",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runArtifactsPull,
+}
+
+// artifactsPruneCmd 清理旧版本
+var artifactsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "按保留规则清理旧版本",
+	RunE:  runArtifactsPrune,
+}
+
+func init() {
+	artifactsCmd.PersistentFlags().StringVar(&artifactProject, "project", "", "项目名称 (默认从配置文件或当前目录获取)")
+
+	artifactsTagCmd.Flags().BoolVar(&untag, "remove", false, "移除标签")
+
+	artifactsPruneCmd.Flags().IntVar(&pruneKeep, "keep", 0, "保留最新的 N 个版本 (默认使用 artifact.store.keep_last)")
+	artifactsPruneCmd.Flags().BoolVar(&pruneKeepTagged, "keep-tagged", true, "保留带标签的版本")
+	artifactsPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "只显示将被删除的版本")
+
+	artifactsCmd.AddCommand(artifactsListCmd)
+	artifactsCmd.AddCommand(artifactsShowCmd)
+	artifactsCmd.AddCommand(artifactsPathCmd)
+	artifactsCmd.AddCommand(artifactsTagCmd)
+	artifactsCmd.AddCommand(artifactsPruneCmd)
+	artifactsCmd.AddCommand(artifactsPushCmd)
+	artifactsCmd.AddCommand(artifactsPullCmd)
+}
+
+// loadArtifactStore 加载仓库和项目名称，从 dir 开始查找配置文件
+func loadArtifactStore(dir string) (*store.Store, string, *config.Config) {
+	cfg, err := loadConfig(dir)
+	if err != nil {
+		cfg = config.GetDefaultConfig()
+		cfg.Project.Name = ""
+	}
+
+	project := artifactProject
+	if project == "" {
+		project = cfg.Project.Name
+	}
+	if project == "" || project == "my-app" {
+		absPath, _ := filepath.Abs(dir)
+		project = utils.GetProjectName(absPath)
+	}
+
+	return store.New(cfg.Artifact.Store.Path), project, cfg
+}
+
+// loadRemoteStore 根据 artifact.store.s3 配置创建远程仓库
+func loadRemoteStore(cfg *config.Config) (*store.Remote, error) {
+	s3Config := cfg.Artifact.Store.S3
+	if s3Config.Bucket == "" {
+		return nil, fmt.Errorf("未配置远程仓库，请在配置文件中设置 artifact.store.s3.bucket")
+	}
+
+	accessKey := s3Config.AccessKey
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	secretKey := s3Config.SecretKey
+	if secretKey == "" {
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+
+	client, err := s3.NewClient(s3.Options{
+		Endpoint:         s3Config.Endpoint,
+		Region:           s3Config.Region,
+		Bucket:           s3Config.Bucket,
+		AccessKey:        accessKey,
+		SecretKey:        secretKey,
+		VirtualHostStyle: s3Config.VirtualHostStyle,
+		PartSize:         int64(s3Config.PartSize) * 1024 * 1024,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建远程仓库失败: %w", err)
+	}
+
+	return store.NewRemote(client, s3Config.Prefix), nil
+}
+
+// runArtifactsList 列出版本
+func runArtifactsList(cmd *cobra.Command, args []string) error {
+	artifactStore, project, _ := loadArtifactStore(".")
+
+	records, err := artifactStore.List(project)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		utils.PrintInfo(fmt.Sprintf("项目 %s 没有构建产物 (%s)", project, artifactStore.Root()))
+		return nil
+	}
+
+	fmt.Printf("🗄️  %s 的构建产物 (%d 个):\n\n", project, len(records))
+	fmt.Printf("  %-24s %-20s %-10s %-8s %s\n", "版本号", "构建时间", "大小", "构建器", "标签")
+	for _, record := range records {
+		fmt.Printf("  %-24s %-20s %-10s %-8s %s\n",
+			record.Version,
+			record.CreatedAt.Format("2006-01-02 15:04:05"),
+			utils.FormatFileSize(record.Size),
+			record.Builder,
+			strings.Join(record.Tags, ","),
+		)
+	}
+
+	return nil
+}
+
+// runArtifactsShow 显示版本详情
+func runArtifactsShow(cmd *cobra.Command, args []string) error {
+	artifactStore, project, _ := loadArtifactStore(".")
+
+	record, err := artifactStore.Get(project, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📦 %s@%s\n", record.Project, record.Version)
+	fmt.Printf("  构建产物: %s\n", artifactStore.ArtifactPath(record))
+	fmt.Printf("  构建器: %s\n", record.Builder)
+	fmt.Printf("  文件大小: %s\n", utils.FormatFileSize(record.Size))
+	fmt.Printf("  SHA-256: %s\n", record.Checksum)
+	fmt.Printf("  构建时间: %s\n", record.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  构建耗时: %s\n", record.BuildTime)
+	fmt.Printf("  包含文件: %d 个\n", len(record.Files))
+	if len(record.Tags) > 0 {
+		fmt.Printf("  标签: %s\n", strings.Join(record.Tags, ", "))
+	}
+
+	return nil
+}
+
+// runArtifactsPath 输出构建产物路径，便于在脚本中使用
+func runArtifactsPath(cmd *cobra.Command, args []string) error {
+	artifactStore, project, _ := loadArtifactStore(".")
+
+	record, err := artifactStore.Get(project, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(artifactStore.ArtifactPath(record))
+	return nil
+}
+
+// runArtifactsTag 添加或移除标签
+func runArtifactsTag(cmd *cobra.Command, args []string) error {
+	artifactStore, project, _ := loadArtifactStore(".")
+	version, tag := args[0], args[1]
+
+	if untag {
+		if err := artifactStore.Untag(project, version, tag); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已移除 %s@%s 的标签: %s", project, version, tag))
+		return nil
+	}
+
+	if err := artifactStore.Tag(project, version, tag); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已为 %s@%s 添加标签: %s", project, version, tag))
+	return nil
+}
+
+// runArtifactsPrune 清理旧版本
+func runArtifactsPrune(cmd *cobra.Command, args []string) error {
+	artifactStore, project, cfg := loadArtifactStore(".")
+
+	keep := pruneKeep
+	if !cmd.Flags().Changed("keep") {
+		keep = cfg.Artifact.Store.KeepLast
+	}
+	if keep <= 0 {
+		return fmt.Errorf("请使用 --keep 指定保留的版本数量")
+	}
+
+	removed, err := artifactStore.Prune(project, store.PruneOptions{
+		KeepLast:   keep,
+		KeepTagged: pruneKeepTagged,
+		DryRun:     pruneDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		utils.PrintInfo("没有需要清理的版本")
+		return nil
+	}
+
+	action := "已删除"
+	if pruneDryRun {
+		action = "将删除"
+	}
+	for _, record := range removed {
+		fmt.Printf("  🗑️  %s %s@%s\n", action, record.Project, record.Version)
+	}
+	utils.PrintSuccess(fmt.Sprintf("%s %d 个版本", action, len(removed)))
+
+	return nil
+}
+
+// runArtifactsPush 上传版本到远程仓库
+func runArtifactsPush(cmd *cobra.Command, args []string) error {
+	artifactStore, project, cfg := loadArtifactStore(".")
+
+	remote, err := loadRemoteStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	record, err := artifactStore.Get(project, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("☁️  上传 %s@%s 到 s3://%s...\n", record.Project, record.Version, cfg.Artifact.Store.S3.Bucket)
+	if err := remote.Push(artifactStore, record); err != nil {
+		return err
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("已上传 %s@%s", record.Project, record.Version))
+	return nil
+}
+
+// runArtifactsPull 从远程仓库下载版本到本地仓库并输出构建产物路径
+func runArtifactsPull(cmd *cobra.Command, args []string) error {
+	artifactStore, project, cfg := loadArtifactStore(".")
+
+	remote, err := loadRemoteStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("☁️  从 s3://%s 下载 %s@%s...\n", cfg.Artifact.Store.S3.Bucket, project, args[0])
+	record, err := remote.Pull(artifactStore, project, args[0])
+	if err != nil {
+		return err
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("已下载 %s@%s (SHA-256: %s)", record.Project, record.Version, record.Checksum))
+	fmt.Println(artifactStore.ArtifactPath(record))
+	return nil
+}
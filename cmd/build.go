@@ -4,7 +4,9 @@ import (
 	"deploy/internal/builder"
 	"deploy/internal/config"
 	"deploy/internal/detector"
+	"deploy/internal/store"
 	"deploy/internal/utils"
+	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -12,11 +14,14 @@ import (
 )
 
 var (
-	buildType   string
-	outputPath  string
-	version     string
-	skipTests   bool
-	projectPath string
+	buildType    string
+	outputPath   string
+	version      string
+	skipTests    bool
+	reproducible bool
+	noStore      bool
+	noCache      bool
+	projectPath  string
 )
 
 // buildCmd 构建命令
@@ -29,6 +34,9 @@ var buildCmd = &cobra.Command{
 - npm: Node.js 项目
 - maven: Maven Java 项目  
 - gradle: Gradle Java 项目
+- go: Go 项目
+- python: Python 项目
+- docker: Docker 镜像 (使用 Dockerfile，或根据项目类型生成)
 - auto: 自动检测项目类型
 
 示例：
@@ -36,18 +44,25 @@ var buildCmd = &cobra.Command{
   deploy build ./my-app                  # 构建指定目录项目
   deploy build --type=npm                # 构建 NPM 项目
   deploy build --type=maven              # 构建 Maven 项目
+  deploy build --type=docker             # 构建 Docker 镜像
   deploy build --path=./my-app           # 使用 --path 指定目录
   deploy build --output=./dist           # 指定输出目录
   deploy build --version=1.0.0           # 指定版本号
-  deploy build --skip-tests              # 跳过测试`,
+  deploy build --skip-tests              # 跳过测试
+  deploy build --reproducible            # 生成可重现的构建产物
+  deploy build --no-store                # 不保存到本地构建产物仓库，同时不使用构建缓存
+  deploy build --no-cache                # 忽略构建缓存，强制重新构建`,
 	RunE: runBuild,
 }
 
 func init() {
-	buildCmd.Flags().StringVarP(&buildType, "type", "t", "auto", "项目类型 (npm, maven, gradle, auto)")
-	buildCmd.Flags().StringVarP(&outputPath, "output", "o", "", "输出目录 (默认为 ./build)")
+	buildCmd.Flags().StringVarP(&buildType, "type", "t", "auto", "项目类型 (npm, maven, gradle, go, python, docker, auto)")
+	buildCmd.Flags().StringVarP(&outputPath, "output", "o", "", "输出目录，相对路径相对项目目录 (默认为 build)")
 	buildCmd.Flags().StringVar(&version, "version", "", "版本号 (默认为时间戳)")
 	buildCmd.Flags().BoolVar(&skipTests, "skip-tests", false, "跳过测试")
+	buildCmd.Flags().BoolVar(&reproducible, "reproducible", false, "生成可重现的构建产物")
+	buildCmd.Flags().BoolVar(&noStore, "no-store", false, "不保存到本地构建产物仓库")
+	buildCmd.Flags().BoolVar(&noCache, "no-cache", false, "忽略构建缓存，强制重新构建")
 	buildCmd.Flags().StringVarP(&projectPath, "path", "p", ".", "项目路径")
 }
 
@@ -71,11 +86,13 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("获取项目绝对路径失败: %w", err)
 	}
 
-	// 加载配置
-	cfg, err := loadConfig()
-	if err != nil {
-		utils.PrintWarning(fmt.Sprintf("加载配置失败，使用默认配置: %v", err))
+	// 加载配置，从项目路径开始查找配置文件，只有找不到配置文件时使用默认配置
+	cfg, err := loadConfig(absProjectPath)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		utils.PrintWarning(fmt.Sprintf("%v，使用默认配置", err))
 		cfg = config.GetDefaultConfig()
+	} else if err != nil {
+		return err
 	}
 
 	// 如果没有指定项目名称，从路径推断
@@ -83,6 +100,11 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		cfg.Project.Name = utils.GetProjectName(absProjectPath)
 	}
 
+	// 未通过命令行指定时使用配置文件中的项目类型
+	if !cmd.Flags().Changed("type") && cfg.Project.Type != "" {
+		buildType = cfg.Project.Type
+	}
+
 	// 检测项目类型
 	var projectType detector.ProjectType
 	if buildType == "auto" {
@@ -100,6 +122,12 @@ func runBuild(cmd *cobra.Command, args []string) error {
 			projectType = detector.ProjectTypeMaven
 		case "gradle":
 			projectType = detector.ProjectTypeGradle
+		case "go":
+			projectType = detector.ProjectTypeGo
+		case "python":
+			projectType = detector.ProjectTypePython
+		case "docker":
+			projectType = detector.ProjectTypeDocker
 		default:
 			return fmt.Errorf("不支持的项目类型: %s", buildType)
 		}
@@ -108,12 +136,20 @@ func runBuild(cmd *cobra.Command, args []string) error {
 
 	// 创建构建选项
 	buildOptions := &builder.BuildOptions{
-		ProjectPath: absProjectPath,
-		Environment: "build",
-		OutputPath:  outputPath,
-		Version:     version,
-		Verbose:     verbose,
-		SkipTests:   skipTests,
+		ProjectType:  projectType,
+		ProjectPath:  absProjectPath,
+		Environment:  "build",
+		OutputPath:   outputPath,
+		Version:      version,
+		Verbose:      verbose,
+		SkipTests:    skipTests,
+		Reproducible: reproducible,
+		NoCache:      noCache,
+	}
+
+	// 构建缓存复用本地构建产物仓库中的版本
+	if !noStore {
+		buildOptions.Store = store.New(cfg.Artifact.Store.Path)
 	}
 
 	// 执行构建
@@ -132,25 +168,119 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		if len(result.Files) > 0 {
 			fmt.Printf("📁 包含文件: %d 个\n", len(result.Files))
 		}
+		if result.Checksum != "" {
+			fmt.Printf("🔐 SHA-256: %s\n", result.Checksum)
+			fmt.Printf("📝 构建清单: %s\n", result.ManifestPath)
+		}
+		if result.Image != "" {
+			fmt.Printf("🐳 镜像: %s\n", result.Image)
+			fmt.Printf("🆔 镜像 ID: %s\n", result.ImageID)
+			if result.ImageDigest != "" {
+				fmt.Printf("🔖 镜像摘要: %s\n", result.ImageDigest)
+			}
+		}
+		if result.CacheHit {
+			fmt.Println("♻️  构建缓存: 命中")
+		} else if result.CacheKey != "" {
+			fmt.Println("♻️  构建缓存: 未命中")
+		}
 	} else {
 		utils.PrintError(fmt.Sprintf("构建失败: %s", result.Message))
 		return fmt.Errorf("构建失败")
 	}
 
+	// 保存到本地构建产物仓库，命中缓存时版本已在仓库中
+	if !noStore && !result.CacheHit {
+		saveToStore(cfg, result)
+	}
+
 	return nil
 }
 
-// loadConfig 加载配置文件
-func loadConfig() (*config.Config, error) {
-	configPath := configFile
-	if configPath == "" {
-		configPath = "deploy.yaml"
+// saveToStore 保存构建产物到本地仓库，失败时只给出警告
+func saveToStore(cfg *config.Config, result *builder.BuildResult) {
+	artifactStore := store.New(cfg.Artifact.Store.Path)
+	record := &store.Record{
+		Project:   cfg.Project.Name,
+		Version:   result.Version,
+		Builder:   result.Builder,
+		Checksum:  result.Checksum,
+		Size:      result.Size,
+		BuildTime: result.BuildTime,
+		Files:     result.Files,
+		CacheKey:  result.CacheKey,
+	}
+
+	if err := artifactStore.Save(record, result.ArtifactPath); err != nil {
+		utils.PrintWarning(fmt.Sprintf("保存到构建产物仓库失败: %v", err))
+		return
 	}
+	fmt.Printf("🗄️  已保存到构建产物仓库: %s@%s\n", record.Project, record.Version)
 
-	// 如果配置文件不存在，返回错误但不终止程序
-	if !utils.FileExists(configPath) {
-		return nil, fmt.Errorf("配置文件不存在: %s", configPath)
+	// 配置了远程仓库时自动上传
+	if cfg.Artifact.Store.S3.Bucket != "" {
+		pushToRemote(cfg, artifactStore, record)
 	}
 
+	// 按保留规则自动清理
+	if keep := cfg.Artifact.Store.KeepLast; keep > 0 {
+		removed, err := artifactStore.Prune(cfg.Project.Name, store.PruneOptions{KeepLast: keep, KeepTagged: true})
+		if err != nil {
+			utils.PrintWarning(fmt.Sprintf("清理构建产物仓库失败: %v", err))
+			return
+		}
+		if len(removed) > 0 {
+			fmt.Printf("🧹 已清理 %d 个旧版本\n", len(removed))
+		}
+	}
+}
+
+// pushToRemote 上传构建产物到远程仓库，失败时只给出警告
+func pushToRemote(cfg *config.Config, artifactStore *store.Store, record *store.Record) {
+	remote, err := loadRemoteStore(cfg)
+	if err == nil {
+		fmt.Printf("☁️  上传到 s3://%s...\n", cfg.Artifact.Store.S3.Bucket)
+		err = remote.Push(artifactStore, record)
+	}
+	if err != nil {
+		utils.PrintWarning(fmt.Sprintf("上传到远程仓库失败: %v", err))
+		return
+	}
+	fmt.Printf("✓ 已上传到远程仓库: %s@%s\n", record.Project, record.Version)
+}
+
+// loadConfig 加载配置文件，未指定 --config 时从 dir 开始向上级目录查找
+func loadConfig(dir string) (*config.Config, error) {
+	configPath, err := resolveConfigPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if strictConfig {
+		return config.LoadConfigStrict(configPath)
+	}
 	return config.LoadConfig(configPath)
 }
+
+// loadEnvironmentConfig 从 dir 开始查找配置文件，并合并指定环境的覆盖配置 (environments.<环境> 和 deploy.<环境>.yaml)
+func loadEnvironmentConfig(dir, envName string) (*config.Config, error) {
+	configPath, err := resolveConfigPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.LoadEnvironmentConfig(configPath, envName, strictConfig)
+}
+
+// resolveConfigPath 获取配置文件路径，未指定 --config 时在 dir 及其上级目录中查找
+// deploy.yaml、deploy.yml、.deploy.yaml 或 deploy.toml
+func resolveConfigPath(dir string) (string, error) {
+	if configFile != "" {
+		// 如果配置文件不存在，返回错误但不终止程序
+		if !utils.FileExists(configFile) {
+			return "", fmt.Errorf("配置文件不存在: %s", configFile)
+		}
+		return configFile, nil
+	}
+	return config.FindConfig(dir)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"deploy/internal/cache"
+	"deploy/internal/config"
+	"deploy/internal/utils"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheProject string
+
+// cacheCmd 依赖缓存命令
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "管理依赖缓存目录",
+	Long: `管理构建时使用的依赖缓存目录。
+
+启用后 (cache.enabled 或设置 DEPLOY_CACHE_DIR 环境变量)，构建器会使用以下目录：
+- npm: npm_config_cache
+- maven: -Dmaven.repo.local
+- gradle: GRADLE_USER_HOME
+- go: GOMODCACHE
+- pip: PIP_CACHE_DIR
+
+示例：
+  deploy cache info                        # 显示各缓存目录及大小
+  deploy cache clean                       # 清理所有缓存
+  deploy cache clean maven                 # 只清理 Maven 缓存`,
+}
+
+// cacheInfoCmd 显示缓存信息
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "显示依赖缓存目录及大小",
+	RunE:  runCacheInfo,
+}
+
+// cacheCleanCmd 清理缓存
+var cacheCleanCmd = &cobra.Command{
+	Use:       "clean [npm|maven|gradle|go|pip]...",
+	Short:     "清理依赖缓存",
+	ValidArgs: []string{string(cache.KindNPM), string(cache.KindMaven), string(cache.KindGradle), string(cache.KindGo), string(cache.KindPip)},
+	Args:      cobra.OnlyValidArgs,
+	RunE:      runCacheClean,
+}
+
+func init() {
+	cacheCmd.PersistentFlags().StringVar(&cacheProject, "project", "", "项目名称，scope 为 project 时使用 (默认从配置文件或当前目录获取)")
+
+	cacheCmd.AddCommand(cacheInfoCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+}
+
+// loadCacheConfig 加载缓存配置和项目名称，从 dir 开始查找配置文件
+func loadCacheConfig(dir string) (config.CacheConfig, string, error) {
+	cfg, err := loadConfig(dir)
+	if err != nil {
+		cfg = config.GetDefaultConfig()
+		cfg.Project.Name = ""
+	}
+
+	project := cacheProject
+	if project == "" {
+		project = cfg.Project.Name
+	}
+	if project == "" || project == "my-app" {
+		absPath, _ := filepath.Abs(dir)
+		project = utils.GetProjectName(absPath)
+	}
+
+	if err := cache.Validate(cfg.Cache); err != nil {
+		return cfg.Cache, project, err
+	}
+	return cfg.Cache, project, nil
+}
+
+// runCacheInfo 显示各缓存目录及大小
+func runCacheInfo(cmd *cobra.Command, args []string) error {
+	cacheConfig, project, err := loadCacheConfig(".")
+	if err != nil {
+		return err
+	}
+
+	scope := cacheConfig.Scope
+	if scope == "" {
+		scope = cache.ScopeGlobal
+	}
+
+	status := "未启用 (构建器使用默认缓存目录)"
+	if cache.Enabled(cacheConfig) {
+		status = "已启用"
+	}
+
+	fmt.Printf("📂 依赖缓存: %s\n", status)
+	fmt.Printf("  范围: %s\n", scope)
+	if scope == cache.ScopeProject {
+		fmt.Printf("  项目: %s\n", project)
+	}
+	fmt.Println()
+
+	fmt.Printf("  %-8s %-10s %-8s %s\n", "类型", "大小", "文件数", "目录")
+	var total int64
+	for _, dir := range cache.Dirs(cacheConfig, project) {
+		size, files, err := cache.Size(dir.Path)
+		if err != nil {
+			return err
+		}
+		total += size
+		fmt.Printf("  %-8s %-10s %-8d %s\n", dir.Kind, utils.FormatFileSize(size), files, dir.Path)
+	}
+	fmt.Printf("\n  合计: %s\n", utils.FormatFileSize(total))
+
+	return nil
+}
+
+// runCacheClean 清理指定类型的缓存，未指定时清理全部
+func runCacheClean(cmd *cobra.Command, args []string) error {
+	cacheConfig, project, err := loadCacheConfig(".")
+	if err != nil {
+		return err
+	}
+
+	kinds := make(map[cache.Kind]bool)
+	for _, arg := range args {
+		kinds[cache.Kind(arg)] = true
+	}
+
+	var freed int64
+	for _, dir := range cache.Dirs(cacheConfig, project) {
+		if len(kinds) > 0 && !kinds[dir.Kind] {
+			continue
+		}
+
+		size, _, err := cache.Size(dir.Path)
+		if err != nil {
+			return err
+		}
+		if err := cache.Clean(dir.Path); err != nil {
+			return err
+		}
+
+		freed += size
+		fmt.Printf("  🗑️  已清理 %s 缓存: %s (%s)\n", dir.Kind, dir.Path, utils.FormatFileSize(size))
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("共释放 %s", utils.FormatFileSize(freed)))
+	return nil
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	schemaOutput  string
+	showEnv       string
+	migrateDryRun bool
+)
+
+// configCmd 配置文件管理命令
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "配置文件管理",
+	Long: `校验配置文件，查看合并环境覆盖配置后的有效配置，导出配置文件的 JSON Schema。
+
+示例：
+  deploy config show --env=prod                 # 查看 prod 环境的有效配置及每个值的来源
+  deploy config validate                        # 校验 deploy.yaml
+  deploy config validate --config=prod.yaml     # 校验指定的配置文件
+  deploy config migrate                         # 升级旧版本的配置文件
+  deploy config schema -o deploy.schema.json    # 导出 JSON Schema`,
+}
+
+// configValidateCmd 校验配置文件
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "校验配置文件",
+	Long: `校验配置文件并报告所有问题及其所在行号。
+
+同时校验加载配置时合并的用户级配置 (~/.config/deploy/config.yaml) 和各环境的覆盖配置文件
+(deploy.<环境>.yaml)，这些文件只包含部分配置项，不检查必填项。
+
+检查内容：
+- 未知的配置项和类型错误
+- 枚举值 (project.type、artifact.format、environments.<环境>.mode 等)
+- 端口范围、各环境的必填项 (deploy_path、服务器地址)
+- 堆内存大小格式 (例如 512m、2g) 以及最小值不大于最大值
+- Java 命令模板中的变量
+- 引用的文件 (模板、配置文件、额外文件、Dockerfile、密钥文件)
+
+存在错误时以非零状态退出，只有警告时视为通过。`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+// configShowCmd 查看有效配置
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "查看有效配置",
+	Long: `输出合并环境覆盖配置后的有效配置，每个值后以注释标明来源 (文件:行号)。
+
+合并顺序（后者优先）：
+1. 用户级配置 (~/.config/deploy/config.yaml) 中的顶层配置
+2. deploy.yaml 中的顶层配置
+3. 用户级配置和 deploy.yaml 中 environments.<环境> 下的 npm、java、go、python、scripts、deploy 等配置
+4. deploy.<环境>.yaml 中的顶层配置
+5. deploy.<环境>.yaml 中 environments.<环境> 下的覆盖配置
+
+对象逐项合并，标量和列表整体替换，带 !append 标签的列表追加到原列表之后。`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+// configMigrateCmd 升级配置文件
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate [文件]...",
+	Short: "升级旧版本的配置文件",
+	Long: `将配置文件逐个版本升级到当前版本，并写入 version，保留注释和配置项顺序。
+
+未指定文件时升级查找到的配置文件、其旁边的环境覆盖配置文件 (deploy.<环境>.yaml)
+以及用户级配置 (~/.config/deploy/config.yaml)。
+加载旧版本的配置文件时会在内存中自动升级并给出警告，升级后不再提示；
+用户级配置未指定 version 且不需要修改时按当前版本处理。
+
+版本历史：
+  1 → 2  配置项名称改为下划线形式 (例如 buildCommand 改为 build_command)`,
+	RunE: runConfigMigrate,
+}
+
+// configSchemaCmd 导出 JSON Schema
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "导出配置文件的 JSON Schema",
+	Long: `导出 deploy.yaml 的 JSON Schema，供编辑器提供补全和校验。
+
+使用 YAML 插件 (yaml-language-server) 时，在 deploy.yaml 开头添加：
+  # yaml-language-server: $schema=./deploy.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigSchema,
+}
+
+func init() {
+	configShowCmd.Flags().StringVarP(&showEnv, "env", "e", "", "环境名称 (为空时只输出 deploy.yaml)")
+	configSchemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "输出文件 (默认输出到标准输出)")
+	configMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "只显示需要修改的内容，不写入文件")
+
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configMigrateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+}
+
+// runConfigValidate 校验配置文件、用户级配置和各环境的覆盖配置文件
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	configPath, err := resolveConfigPath(".")
+	if err != nil {
+		return err
+	}
+
+	files := []string{configPath}
+	errors := 0
+	report := func(path string, issues []config.Issue) {
+		for _, issue := range issues {
+			message := fmt.Sprintf("%s:%s", path, issue)
+			if issue.Warning {
+				utils.PrintWarning(message)
+				continue
+			}
+			utils.PrintError(message)
+			errors++
+		}
+	}
+
+	issues, err := config.ValidateFile(configPath)
+	if err != nil {
+		return err
+	}
+	report(configPath, issues)
+
+	if userPath := config.UserConfigPath(); userPath != "" && utils.FileExists(userPath) {
+		issues, err := config.ValidateLayerFile(userPath, configPath, "")
+		if err != nil {
+			return fmt.Errorf("%s: %w", userPath, err)
+		}
+		report(userPath, issues)
+		files = append(files, userPath)
+	}
+
+	overlays, _ := filepath.Glob(config.OverlayPath(configPath, "*"))
+	ext := filepath.Ext(configPath)
+	prefix := strings.TrimSuffix(filepath.Base(configPath), ext) + "."
+	for _, overlay := range overlays {
+		envName := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(overlay), prefix), ext)
+		issues, err := config.ValidateLayerFile(overlay, configPath, envName)
+		if err != nil {
+			return fmt.Errorf("%s: %w", overlay, err)
+		}
+		report(overlay, issues)
+		files = append(files, overlay)
+	}
+
+	if errors > 0 {
+		return fmt.Errorf("配置文件校验失败，共 %d 个错误", errors)
+	}
+	utils.PrintSuccess(fmt.Sprintf("配置文件校验通过: %s", strings.Join(files, ", ")))
+	return nil
+}
+
+// runConfigShow 输出有效配置
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	configPath, err := resolveConfigPath(".")
+	if err != nil {
+		return err
+	}
+
+	effective, err := config.ResolveEnvironment(configPath, showEnv, strictConfig)
+	if err != nil {
+		return err
+	}
+	content, err := effective.Render()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("# 有效配置: %s\n", strings.Join(effective.Files, " + "))
+	if len(effective.EnvFiles) > 0 {
+		fmt.Printf("# 变量文件: %s\n", strings.Join(effective.EnvFiles, ", "))
+	}
+	fmt.Print(string(content))
+	return nil
+}
+
+// runConfigMigrate 升级配置文件
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	files := args
+	if len(files) == 0 {
+		configPath, err := resolveConfigPath(".")
+		if err != nil {
+			return err
+		}
+		overlays, _ := filepath.Glob(config.OverlayPath(configPath, "*"))
+		files = append([]string{configPath}, overlays...)
+		if userPath := config.UserConfigPath(); userPath != "" && utils.FileExists(userPath) {
+			files = append(files, userPath)
+		}
+	}
+
+	for _, file := range files {
+		result, content, err := config.MigrateFile(file)
+		if err != nil {
+			return err
+		}
+		if !result.Migrated() {
+			utils.PrintInfo(fmt.Sprintf("%s 已是最新版本 (%d)", file, result.To))
+			continue
+		}
+
+		fmt.Printf("🔄 %s: 版本 %d → %d\n", file, result.From, result.To)
+		for _, change := range result.Changes {
+			fmt.Printf("  - %s\n", change)
+		}
+		if migrateDryRun {
+			continue
+		}
+
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(file); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(file, content, mode); err != nil {
+			return fmt.Errorf("写入配置文件失败: %w", err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("已升级 %s", file))
+	}
+	return nil
+}
+
+// runConfigSchema 导出 JSON Schema
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	schema, err := config.JSONSchema()
+	if err != nil {
+		return err
+	}
+
+	if schemaOutput == "" {
+		fmt.Print(string(schema))
+		return nil
+	}
+	if err := os.WriteFile(schemaOutput, schema, 0644); err != nil {
+		return fmt.Errorf("写入 JSON Schema 失败: %w", err)
+	}
+	utils.PrintSuccess(fmt.Sprintf("已导出 JSON Schema: %s", schemaOutput))
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"deploy/internal/utils"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -19,6 +20,9 @@ var detectCmd = &cobra.Command{
 - NPM 项目 (package.json)
 - Maven 项目 (pom.xml)
 - Gradle 项目 (build.gradle 或 build.gradle.kts)
+- Go 项目 (go.mod)
+- Python 项目 (pyproject.toml、setup.py 或 requirements.txt)
+- Docker 项目 (Dockerfile)
 
 示例：
   deploy detect                    # 检测当前目录
@@ -87,6 +91,29 @@ func runDetect(cmd *cobra.Command, args []string) error {
 		fmt.Println("  ✓ 发现 build.gradle - Gradle 项目")
 	}
 
+	if detector.IsGoProject(absProjectPath) {
+		fmt.Println("  ✓ 发现 go.mod - Go 项目")
+	}
+
+	if projectInfo.Go != nil {
+		fmt.Printf("  模块: %s (go %s)\n", projectInfo.Go.Module, projectInfo.Go.GoVersion)
+		if len(projectInfo.Go.MainPackages) > 0 {
+			fmt.Printf("  main 包: %s\n", strings.Join(projectInfo.Go.MainPackages, ", "))
+		}
+	}
+
+	if detector.IsPythonProject(absProjectPath) {
+		fmt.Println("  ✓ 发现 pyproject.toml/setup.py/requirements.txt - Python 项目")
+	}
+
+	if projectInfo.Python != nil && projectInfo.Python.RequiresPython != "" {
+		fmt.Printf("  要求 Python 版本: %s\n", projectInfo.Python.RequiresPython)
+	}
+
+	if projectInfo.HasDockerfile {
+		fmt.Println("  ✓ 发现 Dockerfile - 可以构建 Docker 镜像")
+	}
+
 	// 给出构建建议
 	fmt.Println("\n💡 构建建议:")
 	switch projectInfo.Type {
@@ -96,9 +123,18 @@ func runDetect(cmd *cobra.Command, args []string) error {
 		fmt.Println("  使用命令: deploy build --type=maven")
 	case detector.ProjectTypeGradle:
 		fmt.Println("  使用命令: deploy build --type=gradle")
+	case detector.ProjectTypeGo:
+		fmt.Println("  使用命令: deploy build --type=go")
+	case detector.ProjectTypePython:
+		fmt.Println("  使用命令: deploy build --type=python")
+	case detector.ProjectTypeDocker:
+		fmt.Println("  使用命令: deploy build --type=docker")
 	default:
 		fmt.Println("  使用命令: deploy build (自动检测)")
 	}
+	if projectInfo.HasDockerfile && projectInfo.Type != detector.ProjectTypeDocker {
+		fmt.Println("  构建 Docker 镜像: deploy build --type=docker")
+	}
 
 	return nil
 }
@@ -2,16 +2,25 @@ package cmd
 
 import (
 	"deploy/internal/config"
+	"deploy/internal/deployer"
 	"deploy/internal/detector"
+	"deploy/internal/templates"
 	"deploy/internal/utils"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	force bool
+	force             bool
+	initYes           bool
+	initTemplate      string
+	initWithScripts   bool
+	initListTemplates bool
 )
 
 // initCmd 初始化命令
@@ -20,23 +29,76 @@ var initCmd = &cobra.Command{
 	Short: "初始化配置文件",
 	Long: `在当前目录或指定目录创建 deploy.yaml 配置文件。
 
-如果检测到项目类型，会自动生成相应的配置。
+在终端中运行时依次询问项目名称、类型、环境、服务器、部署目录、部署模式、服务管理器和
+健康检查地址，默认值来自项目检测结果；使用 --yes 或不在终端中运行时直接使用默认值，
+生成一个部署到本机的 prod 环境。配置文件只包含与默认行为不同的配置项。
+
+使用 --template 从项目模板生成配置文件，只询问项目名称和部署目录。内置模板：
+  spring-boot-jar          Spring Boot 可执行 JAR，systemd 管理
+  spring-boot-war-tomcat   Spring Boot WAR，部署到独立的 Tomcat 实例
+  react-static-nginx       React 静态站点，nginx 提供服务
+  node-express-pm2         Express 服务，PM2 管理
+  next-standalone          Next.js standalone 输出，PM2 管理
+~/.config/deploy/templates/<名称>/ 中的用户模板 (deploy.yaml 和可选的 scripts/ 目录)
+会替代同名的内置模板，--template 也可以直接指定模板目录。
+--with-scripts 同时将模板中的示例脚本 (common.sh、java-deploy.sh、npm-deploy.sh) 写入
+scripts/ 目录，生成的配置文件会使用这些脚本构建或启动服务。
 
 示例：
   deploy init                      # 在当前目录初始化
   deploy init ./my-app             # 在指定目录初始化
   deploy init --path=./my-app      # 使用 --path 指定目录
-  deploy init --force              # 强制覆盖已存在的配置文件`,
+  deploy init --yes                # 不提问，使用默认值
+  deploy init --force              # 强制覆盖已存在的配置文件
+  deploy init --list-templates     # 列出可用的项目模板
+  deploy init --template=spring-boot-jar --with-scripts`,
 	RunE: runInit,
 }
 
 func init() {
 	initCmd.Flags().StringVarP(&projectPath, "path", "p", ".", "项目路径")
 	initCmd.Flags().BoolVarP(&force, "force", "f", false, "强制覆盖已存在的配置文件")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "不提问，使用检测结果和默认值")
+	initCmd.Flags().StringVarP(&initTemplate, "template", "t", "", "使用项目模板生成配置文件 (模板名称或目录)")
+	initCmd.Flags().BoolVar(&initWithScripts, "with-scripts", false, "同时生成模板中的示例脚本")
+	initCmd.Flags().BoolVar(&initListTemplates, "list-templates", false, "列出可用的项目模板")
+}
+
+// initAnswers 初始化时确定的配置，交互模式下来自提问，否则使用检测结果和默认值
+type initAnswers struct {
+	Name         string
+	Type         string
+	Environments []initEnvironment
+}
+
+// initEnvironment 初始化时配置的环境
+type initEnvironment struct {
+	Name           string
+	Servers        []config.ServerConfig
+	DeployPath     string
+	Mode           string
+	Manager        string
+	HealthCheckURL string
 }
 
 // runInit 执行初始化
 func runInit(cmd *cobra.Command, args []string) error {
+	if initListTemplates {
+		return listTemplates()
+	}
+	if initWithScripts && initTemplate == "" {
+		return fmt.Errorf("--with-scripts 需要同时使用 --template 指定项目模板")
+	}
+
+	// 先加载模板，模板不存在时不做其他检查
+	var template *templates.Template
+	if initTemplate != "" {
+		var err error
+		if template, err = templates.Load(initTemplate); err != nil {
+			return err
+		}
+	}
+
 	fmt.Println("🚀 初始化配置文件...")
 
 	// 如果有位置参数，使用第一个参数作为项目路径
@@ -60,48 +122,42 @@ func runInit(cmd *cobra.Command, args []string) error {
 	// 配置文件路径
 	configPath := filepath.Join(absProjectPath, "deploy.yaml")
 
-	// 检查配置文件是否已存在
-	if utils.FileExists(configPath) && !force {
-		return fmt.Errorf("配置文件已存在: %s\n使用 --force 参数强制覆盖", configPath)
+	// 检查配置文件是否已存在，包括 deploy.yml 等其他文件名
+	if !force {
+		for _, name := range config.ConfigFileNames {
+			if existing := filepath.Join(absProjectPath, name); utils.FileExists(existing) {
+				return fmt.Errorf("配置文件已存在: %s\n使用 --force 参数强制覆盖", existing)
+			}
+		}
 	}
 
 	// 检测项目类型
 	fmt.Println("🔍 检测项目类型...")
+	answers := &initAnswers{Name: utils.GetProjectName(absProjectPath), Type: "auto"}
 	projectInfo, err := detector.DetectProject(absProjectPath)
 	if err != nil {
 		utils.PrintWarning(fmt.Sprintf("无法检测项目类型: %v", err))
-		utils.PrintInfo("将使用默认配置")
 	} else {
 		utils.PrintSuccess(fmt.Sprintf("检测到项目类型: %s", projectInfo.Type))
+		answers.Name = projectInfo.Name
+		answers.Type = string(projectInfo.Type)
 	}
 
-	// 创建配置
-	cfg := config.GetDefaultConfig()
-
-	// 根据检测结果调整配置
-	if projectInfo != nil {
-		cfg.Project.Name = projectInfo.Name
-		cfg.Project.Type = string(projectInfo.Type)
+	if template != nil {
+		return initFromTemplate(template, answers, absProjectPath, configPath)
+	}
 
-		// 根据项目类型调整配置
-		switch projectInfo.Type {
-		case detector.ProjectTypeNPM:
-			fmt.Println("📦 配置 NPM 项目设置...")
-			// NPM 配置已经在默认配置中设置好了
-		case detector.ProjectTypeMaven:
-			fmt.Println("☕ 配置 Maven 项目设置...")
-			cfg.Java.BuildTool = "maven"
-		case detector.ProjectTypeGradle:
-			fmt.Println("🐘 配置 Gradle 项目设置...")
-			cfg.Java.BuildTool = "gradle"
-			cfg.Java.BuildCommand = "./gradlew clean build"
-		}
+	// 只有在终端中运行且未指定 --yes 时提问，否则使用检测结果和默认值
+	if !initYes && utils.IsTerminal(os.Stdin) {
+		fmt.Println("\n📝 回答以下问题生成配置，直接回车使用括号中的默认值")
+		askInitAnswers(utils.NewPrompter(os.Stdin, os.Stdout), answers)
+		fmt.Println()
 	} else {
-		// 使用目录名作为项目名
-		cfg.Project.Name = utils.GetProjectName(absProjectPath)
+		answers.Environments = []initEnvironment{defaultInitEnvironment("prod", answers)}
 	}
 
-	// 保存配置文件
+	// 只写入与默认行为不同的配置项
+	cfg := answers.config()
 	fmt.Println("💾 保存配置文件...")
 	if err := config.SaveConfig(cfg, configPath); err != nil {
 		return fmt.Errorf("保存配置文件失败: %w", err)
@@ -113,22 +169,238 @@ func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Println("\n📋 配置摘要:")
 	fmt.Printf("  项目名称: %s\n", cfg.Project.Name)
 	fmt.Printf("  项目类型: %s\n", cfg.Project.Type)
+	for _, env := range answers.Environments {
+		target := "本机"
+		if len(env.Servers) > 0 {
+			hosts := make([]string, len(env.Servers))
+			for i, server := range env.Servers {
+				hosts[i] = server.Host
+			}
+			target = strings.Join(hosts, ", ")
+		}
+		fmt.Printf("  环境 %s: %s 部署到 %s:%s\n", env.Name, env.Mode, target, env.DeployPath)
+	}
 
-	if cfg.Project.Type == "npm" || cfg.Project.Type == "auto" {
-		fmt.Printf("  NPM 构建命令: %s\n", cfg.NPM.BuildCommand)
-		fmt.Printf("  NPM 构建目录: %s\n", cfg.NPM.BuildDir)
+	// 显示下一步建议
+	fmt.Println("\n💡 下一步:")
+	fmt.Println("  1. 运行 'deploy config validate' 检查配置")
+	fmt.Println("  2. 运行 'deploy build' 开始构建项目")
+	if len(answers.Environments) > 0 {
+		fmt.Printf("  3. 运行 'deploy release %s' 部署\n", answers.Environments[0].Name)
 	}
 
-	if cfg.Project.Type == "maven" || cfg.Project.Type == "gradle" || cfg.Project.Type == "auto" {
-		fmt.Printf("  Java 构建工具: %s\n", cfg.Java.BuildTool)
-		fmt.Printf("  Java 构建命令: %s\n", cfg.Java.BuildCommand)
+	return nil
+}
+
+// listTemplates 列出内置模板和用户模板
+func listTemplates() error {
+	list, err := templates.List()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("📋 可用的项目模板:")
+	for _, t := range list {
+		source := "内置"
+		if !t.Builtin() {
+			source = t.Dir
+		}
+		fmt.Printf("  %-24s %s (%s)\n", t.Name, t.Description, source)
+	}
+	if dir := config.UserTemplatesDir(); dir != "" {
+		fmt.Printf("\n💡 在 %s/<名称>/ 中添加 deploy.yaml 和 scripts/ 即可创建自定义模板\n", dir)
+	}
+	return nil
+}
+
+// initFromTemplate 渲染模板生成配置文件，--with-scripts 时同时写入示例脚本
+func initFromTemplate(template *templates.Template, answers *initAnswers, projectDir, configPath string) error {
+	fmt.Printf("📦 使用模板: %s (%s)\n", template.Name, template.Description)
+
+	data := templates.Data{
+		Name:        answers.Name,
+		DeployPath:  "/opt/" + utils.SanitizeFileName(answers.Name),
+		Version:     config.CurrentVersion,
+		WithScripts: initWithScripts,
+	}
+	if !initYes && utils.IsTerminal(os.Stdin) {
+		fmt.Println("\n📝 回答以下问题生成配置，直接回车使用括号中的默认值")
+		prompter := utils.NewPrompter(os.Stdin, os.Stdout)
+		data.Name = prompter.Ask("项目名称", data.Name)
+		data.DeployPath = prompter.Ask("部署目录", "/opt/"+utils.SanitizeFileName(data.Name))
+		fmt.Println()
+	}
+
+	content, err := template.Render(data)
+	if err != nil {
+		return err
+	}
+
+	// 用户模板可能有错误，写入前先校验
+	issues, err := config.Validate(content, projectDir)
+	if err != nil {
+		return fmt.Errorf("模板 %s 生成的配置文件无效: %w", template.Name, err)
+	}
+	for _, issue := range issues {
+		if issue.Warning {
+			utils.PrintWarning(fmt.Sprintf("模板 %s: %s", template.Name, issue))
+		}
+	}
+	if config.HasErrors(issues) {
+		for _, issue := range issues {
+			if !issue.Warning {
+				utils.PrintError(fmt.Sprintf("模板 %s: %s", template.Name, issue))
+			}
+		}
+		return fmt.Errorf("模板 %s 生成的配置文件无效", template.Name)
+	}
+
+	fmt.Println("💾 保存配置文件...")
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		return fmt.Errorf("保存配置文件失败: %w", err)
+	}
+	utils.PrintSuccess(fmt.Sprintf("配置文件已创建: %s", configPath))
+
+	if initWithScripts {
+		written, skipped, err := template.WriteScripts(filepath.Join(projectDir, templates.ScriptsDir), force)
+		if err != nil {
+			return err
+		}
+		for _, path := range written {
+			utils.PrintSuccess(fmt.Sprintf("示例脚本已创建: %s", path))
+		}
+		for _, path := range skipped {
+			utils.PrintWarning(fmt.Sprintf("脚本已存在，未覆盖: %s (使用 --force 覆盖)", path))
+		}
+		if len(template.Scripts()) == 0 {
+			utils.PrintWarning(fmt.Sprintf("模板 %s 没有示例脚本", template.Name))
+		}
 	}
 
-	// 显示下一步建议
 	fmt.Println("\n💡 下一步:")
-	fmt.Println("  1. 编辑 deploy.yaml 文件以自定义配置")
-	fmt.Println("  2. 运行 'deploy detect' 验证项目检测")
+	fmt.Println("  1. 检查 deploy.yaml 中的服务器、端口和健康检查地址")
+	fmt.Println("  2. 运行 'deploy config validate' 检查配置")
 	fmt.Println("  3. 运行 'deploy build' 开始构建项目")
-
 	return nil
 }
+
+// askInitAnswers 交互式询问项目和各环境的配置，以检测结果作为默认值
+func askInitAnswers(prompter *utils.Prompter, answers *initAnswers) {
+	answers.Name = prompter.Ask("项目名称", answers.Name)
+	answers.Type = prompter.Select("项目类型", utils.ProjectTypes, answers.Type)
+
+	names := prompter.Ask("环境 (多个用逗号分隔)", "prod")
+	for _, name := range splitList(names) {
+		fmt.Printf("\n🌐 环境 %s\n", name)
+		env := defaultInitEnvironment(name, answers)
+
+		for {
+			servers, err := parseServers(prompter.Ask("服务器 (user@host:port，多个用逗号分隔，留空为本机部署)", ""))
+			if err == nil {
+				env.Servers = servers
+				break
+			}
+			utils.PrintWarning(err.Error())
+		}
+		env.DeployPath = prompter.Ask("部署目录", env.DeployPath)
+		env.Mode = prompter.Select("部署模式", []string{deployer.ModeService, deployer.ModeStatic}, env.Mode)
+		if env.Mode == deployer.ModeService {
+			managers := []string{deployer.ManagerNohup, deployer.ManagerSystemd, deployer.ManagerPM2}
+			if answers.Type == string(detector.ProjectTypeNPM) {
+				// nohup 需要自行编写后台运行的启停命令，Node 服务使用 PM2 或 systemd
+				managers = managers[1:]
+			}
+			env.Manager = prompter.Select("服务管理器", managers, env.Manager)
+			env.HealthCheckURL = prompter.Ask("健康检查地址 (留空不检查)", env.HealthCheckURL)
+		}
+
+		answers.Environments = append(answers.Environments, env)
+	}
+}
+
+// defaultInitEnvironment 根据项目类型生成环境的默认配置：NPM 项目部署为静态站点，其他项目部署为服务
+func defaultInitEnvironment(name string, answers *initAnswers) initEnvironment {
+	env := initEnvironment{
+		Name:       name,
+		DeployPath: "/opt/" + utils.SanitizeFileName(answers.Name),
+		Mode:       deployer.ModeService,
+		Manager:    deployer.ManagerSystemd,
+	}
+	if answers.Type == string(detector.ProjectTypeNPM) {
+		env.Mode = deployer.ModeStatic
+		env.Manager = deployer.ManagerPM2
+	}
+	return env
+}
+
+// config 生成只包含非默认值的配置
+func (a *initAnswers) config() *config.Config {
+	defaults := config.GetDefaultConfig()
+	cfg := &config.Config{
+		Version: config.CurrentVersion,
+		Project: config.ProjectConfig{Name: a.Name, Type: a.Type},
+	}
+	if a.Type == "auto" {
+		cfg.Project.Type = ""
+	}
+
+	for _, env := range a.Environments {
+		envConfig := config.EnvironmentConfig{
+			Servers:        env.Servers,
+			DeployPath:     env.DeployPath,
+			Mode:           env.Mode,
+			HealthCheckURL: env.HealthCheckURL,
+		}
+		if env.Mode == deployer.ModeService && env.Manager != deployer.ManagerNohup {
+			envConfig.Service.Manager = env.Manager
+		}
+		if cfg.Environments == nil {
+			cfg.Environments = make(map[string]config.EnvironmentConfig)
+		}
+		cfg.Environments[env.Name] = envConfig
+
+		// nohup 使用配置中的启停命令，没有内置默认值
+		if env.Mode == deployer.ModeService && env.Manager == deployer.ManagerNohup {
+			cfg.Java.Runtime.HeapSize = defaults.Java.Runtime.HeapSize
+			cfg.Java.DefaultStartCommand = defaults.Java.DefaultStartCommand
+			cfg.Java.DefaultStopCommand = defaults.Java.DefaultStopCommand
+			cfg.Java.DefaultStatusCommand = defaults.Java.DefaultStatusCommand
+		}
+	}
+
+	return cfg
+}
+
+// parseServers 解析 user@host:port 形式的服务器列表
+func parseServers(value string) ([]config.ServerConfig, error) {
+	var servers []config.ServerConfig
+	for _, item := range splitList(value) {
+		server := config.ServerConfig{Host: item}
+		if user, host, ok := strings.Cut(server.Host, "@"); ok {
+			server.User, server.Host = user, host
+		}
+		if host, port, ok := strings.Cut(server.Host, ":"); ok {
+			number, err := strconv.Atoi(port)
+			if err != nil || number < 1 || number > 65535 {
+				return nil, fmt.Errorf("端口无效: %s", item)
+			}
+			server.Host, server.Port = host, number
+		}
+		if server.Host == "" {
+			return nil, fmt.Errorf("服务器地址不能为空: %s", item)
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+// splitList 按逗号分隔并去掉空白项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/detector"
+	"deploy/internal/publish"
+	"deploy/internal/store"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	publishArtifact string
+	publishTarget   string
+)
+
+// publishCmd 发布命令
+var publishCmd = &cobra.Command{
+	Use:   "publish [版本号]",
+	Short: "发布构建产物到 Maven 仓库或 npm 仓库",
+	Long: `将本地构建产物仓库中的构建产物发布到远程仓库。
+
+- Maven/Gradle 项目：通过 HTTP PUT 上传到 Maven 仓库（Nexus/Artifactory 目录结构），
+  同时上传生成的 POM、.md5/.sha1/.sha256 校验文件，并更新 maven-metadata.xml
+- NPM 项目：使用配置中的 token 发布到 npm 仓库，publish.npm.mode 为 package 时
+  发布 npm pack 生成的包，为 artifact 时发布构建产物
+
+版本号默认为 latest（最新构建的版本）。
+
+示例：
+  deploy publish                              # 发布最新构建的版本
+  deploy publish 1.0.0                        # 发布指定版本
+  deploy publish --artifact=./build/app.jar   # 直接发布指定文件
+  deploy publish --target=npm                 # 指定发布目标`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPublish,
+}
+
+func init() {
+	publishCmd.Flags().StringVarP(&projectPath, "path", "p", ".", "项目路径")
+	publishCmd.Flags().StringVar(&publishArtifact, "artifact", "", "直接指定要发布的构建产物")
+	publishCmd.Flags().StringVar(&publishTarget, "target", "", "发布目标 (maven, npm)，默认根据项目类型判断")
+	publishCmd.Flags().StringVar(&version, "version", "", "版本号 (使用 --artifact 时默认从文件名推断)")
+}
+
+// runPublish 执行发布
+func runPublish(cmd *cobra.Command, args []string) error {
+	fmt.Println("🚀 开始发布...")
+
+	if !utils.DirExists(projectPath) {
+		return fmt.Errorf("项目路径不存在: %s", projectPath)
+	}
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return fmt.Errorf("获取项目绝对路径失败: %w", err)
+	}
+
+	// 从项目路径开始查找配置文件
+	cfg, err := loadConfig(absProjectPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Project.Name == "" || cfg.Project.Name == "my-app" {
+		cfg.Project.Name = utils.GetProjectName(absProjectPath)
+	}
+
+	// 确定构建产物和版本号
+	artifactPath, artifactVersion, builderType, err := resolvePublishArtifact(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	target := publishTarget
+	if target == "" {
+		target = publishTargetFor(builderType, absProjectPath)
+	}
+
+	fmt.Printf("📦 构建产物: %s\n", artifactPath)
+	fmt.Printf("📋 版本号: %s\n", artifactVersion)
+
+	switch target {
+	case "maven":
+		return publishMaven(cfg, artifactPath, artifactVersion)
+	case "npm":
+		return publishNPM(cfg, absProjectPath, artifactPath)
+	default:
+		return fmt.Errorf("无法确定发布目标，请使用 --target 指定 (maven, npm)")
+	}
+}
+
+// resolvePublishArtifact 获取要发布的构建产物路径、版本号和构建器类型
+func resolvePublishArtifact(cfg *config.Config, args []string) (string, string, string, error) {
+	if publishArtifact != "" {
+		if !utils.FileExists(publishArtifact) {
+			return "", "", "", fmt.Errorf("构建产物不存在: %s", publishArtifact)
+		}
+		artifactVersion := version
+		if artifactVersion == "" {
+			artifactVersion = versionFromArtifactName(cfg.Project.Name, publishArtifact)
+		}
+		if artifactVersion == "" {
+			return "", "", "", fmt.Errorf("无法从文件名推断版本号，请使用 --version 指定")
+		}
+		return publishArtifact, artifactVersion, "", nil
+	}
+
+	requested := "latest"
+	if len(args) > 0 {
+		requested = args[0]
+	}
+
+	artifactStore := store.New(cfg.Artifact.Store.Path)
+	record, err := artifactStore.Get(cfg.Project.Name, requested)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return artifactStore.ArtifactPath(record), record.Version, record.Builder, nil
+}
+
+// publishTargetFor 根据构建器类型或项目类型确定发布目标
+func publishTargetFor(builderType, absProjectPath string) string {
+	if builderType == "" {
+		if info, err := detector.DetectProject(absProjectPath); err == nil {
+			builderType = string(info.Type)
+		}
+	}
+
+	switch detector.ProjectType(builderType) {
+	case detector.ProjectTypeMaven, detector.ProjectTypeGradle:
+		return "maven"
+	case detector.ProjectTypeNPM:
+		return "npm"
+	}
+	return ""
+}
+
+// versionFromArtifactName 从 <name>-<version>.<ext> 格式的文件名推断版本号
+func versionFromArtifactName(projectName, artifactPath string) string {
+	name := filepath.Base(artifactPath)
+	if !strings.HasPrefix(name, projectName+"-") {
+		return ""
+	}
+	name = strings.TrimPrefix(name, projectName+"-")
+	return strings.TrimSuffix(name, "."+artifactPackaging(name))
+}
+
+// artifactPackaging 根据文件名获取 Maven packaging (jar、tar.gz、zip 等)
+func artifactPackaging(name string) string {
+	for _, ext := range []string{".tar.gz", ".tar.zst"} {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimPrefix(ext, ".")
+		}
+	}
+	return strings.TrimPrefix(filepath.Ext(name), ".")
+}
+
+// publishMaven 发布到 Maven 仓库
+func publishMaven(cfg *config.Config, artifactPath, artifactVersion string) error {
+	if info, err := os.Stat(artifactPath); err == nil && info.IsDir() {
+		return fmt.Errorf("目录格式的构建产物不能发布到 Maven 仓库")
+	}
+
+	publisher, err := publish.NewMavenPublisher(cfg.Publish.Maven)
+	if err != nil {
+		return err
+	}
+
+	artifactID := cfg.Publish.Maven.ArtifactID
+	if artifactID == "" {
+		artifactID = cfg.Project.Name
+	}
+
+	artifact := publish.MavenArtifact{
+		GroupID:    cfg.Publish.Maven.GroupID,
+		ArtifactID: artifactID,
+		Version:    artifactVersion,
+		Packaging:  artifactPackaging(artifactPath),
+		File:       artifactPath,
+	}
+
+	fmt.Printf("☕ 发布到 Maven 仓库: %s:%s:%s\n", artifact.GroupID, artifact.ArtifactID, artifact.Version)
+	uploaded, err := publisher.Publish(artifact)
+	if verbose {
+		for _, u := range uploaded {
+			fmt.Printf("  ✓ %s\n", u)
+		}
+	}
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("发布失败: %v", err))
+		return err
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("已发布 %s:%s:%s (%d 个文件)", artifact.GroupID, artifact.ArtifactID, artifact.Version, len(uploaded)))
+	return nil
+}
+
+// publishNPM 发布到 npm 仓库
+func publishNPM(cfg *config.Config, absProjectPath, artifactPath string) error {
+	publisher, err := publish.NewNPMPublisher(cfg.Publish.NPM)
+	if err != nil {
+		return err
+	}
+
+	var tarball string
+	switch cfg.Publish.NPM.Mode {
+	case "", publish.NPMModePackage:
+		fmt.Println("📦 执行 npm pack...")
+		tarball, err = publish.PackProject(absProjectPath)
+		if err != nil {
+			return err
+		}
+	case publish.NPMModeArtifact:
+		fmt.Println("📦 将构建产物打包为 npm 包...")
+		tmpDir, err := os.MkdirTemp("", "deploy-publish-")
+		if err != nil {
+			return fmt.Errorf("创建临时目录失败: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		tarball = filepath.Join(tmpDir, "package.tgz")
+		if err := publish.PackArtifact(artifactPath, filepath.Join(absProjectPath, "package.json"), tarball); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("不支持的 npm 发布模式: %s", cfg.Publish.NPM.Mode)
+	}
+
+	name, pkgVersion, err := publisher.Publish(tarball)
+	if cfg.Publish.NPM.Mode != publish.NPMModeArtifact {
+		os.Remove(tarball)
+	}
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("发布失败: %v", err))
+		return err
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("已发布 %s@%s", name, pkgVersion))
+	return nil
+}
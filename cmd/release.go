@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/deployer"
+	"deploy/internal/manifest"
+	"deploy/internal/store"
+	"deploy/internal/utils"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseArtifact string
+	releaseProject  string
+)
+
+// releaseCmd 部署命令
+var releaseCmd = &cobra.Command{
+	Use:   "release <环境> [版本号]",
+	Short: "将构建产物部署到指定环境",
+	Long: `将构建产物部署到 environments 中配置的环境。
+
+构建产物默认从本地构建产物仓库中获取，不指定版本号时使用最新版本；本地仓库中没有时，
+如果配置了远程仓库 (artifact.store.s3)，先下载到本地仓库再部署。
+也可以通过 --artifact 直接指定构建产物文件。
+
+支持的部署模式 (environments.<环境>.mode)：
+- static: 静态站点，解压到版本目录后切换 nginx 使用的符号链接
+- service: Java/Node 服务，切换版本后由服务管理器 (nohup、systemd、pm2) 重启服务
+
+示例：
+  deploy release prod                      # 部署最新版本
+  deploy release prod 1.2.0                # 部署指定版本
+  deploy release prod --artifact=./build/my-app-1.2.0.tar.gz  # 部署指定的构建产物`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRelease,
+}
+
+func init() {
+	releaseCmd.Flags().StringVar(&releaseArtifact, "artifact", "", "构建产物路径 (默认从本地构建产物仓库获取)")
+	releaseCmd.Flags().StringVar(&releaseProject, "project", "", "项目名称 (默认从配置文件获取)")
+}
+
+// runRelease 执行部署
+func runRelease(cmd *cobra.Command, args []string) error {
+	cfg, err := loadEnvironmentConfig(".", args[0])
+	if err != nil {
+		return err
+	}
+
+	environment := args[0]
+	if err := deployer.CheckLocal(environment, cfg.Environments[environment]); err != nil {
+		return err
+	}
+	version := "latest"
+	if len(args) > 1 {
+		version = args[1]
+	}
+
+	options := &deployer.Options{
+		Environment: environment,
+		ProjectPath: ".",
+		Verbose:     verbose,
+	}
+
+	if releaseArtifact != "" {
+		// 未指定版本号时从构建清单中读取
+		if len(args) < 2 {
+			m, err := manifest.Load(manifest.ManifestPath(releaseArtifact))
+			if err != nil {
+				return fmt.Errorf("读取构建清单失败，请指定版本号: %w", err)
+			}
+			version = m.Version
+			options.Builder = m.Builder
+		}
+		options.ArtifactPath = releaseArtifact
+		options.Version = version
+	} else {
+		project := releaseProject
+		if project == "" {
+			project = cfg.Project.Name
+		}
+
+		artifactStore := store.New(cfg.Artifact.Store.Path)
+		record, err := artifactStore.Get(project, version)
+		if errors.Is(err, store.ErrNotFound) && cfg.Artifact.Store.S3.Bucket != "" {
+			record, err = pullRelease(cfg, artifactStore, project, version)
+		}
+		if err != nil {
+			return err
+		}
+		options.ArtifactPath = artifactStore.ArtifactPath(record)
+		options.Version = record.Version
+		options.Builder = record.Builder
+	}
+
+	// 部署前校验构建产物
+	if err := manifest.VerifyChecksum(options.ArtifactPath); err != nil {
+		return fmt.Errorf("校验构建产物失败: %w", err)
+	}
+	fmt.Printf("🚀 部署 %s %s 到 %s\n", cfg.Project.Name, options.Version, environment)
+	fmt.Printf("📦 构建产物: %s\n", filepath.Base(options.ArtifactPath))
+
+	return deployer.Deploy(cfg, options)
+}
+
+// pullRelease 本地仓库中没有要部署的版本时，从远程仓库下载到本地仓库
+func pullRelease(cfg *config.Config, artifactStore *store.Store, project, version string) (*store.Record, error) {
+	remote, err := loadRemoteStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("☁️  本地仓库中没有 %s@%s，从 s3://%s 下载...\n", project, version, cfg.Artifact.Store.S3.Bucket)
+	record, err := remote.Pull(artifactStore, project, version)
+	if err != nil {
+		return nil, err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已下载 %s@%s (SHA-256: %s)", record.Project, record.Version, record.Checksum))
+	return record, nil
+}
@@ -7,8 +7,9 @@ import (
 )
 
 var (
-	configFile string
-	verbose    bool
+	configFile   string
+	verbose      bool
+	strictConfig bool
 )
 
 // rootCmd 根命令
@@ -40,13 +41,22 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// 全局标志
-	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "配置文件路径 (默认为 deploy.yaml)")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "配置文件路径 (默认在当前目录及其上级目录中查找 deploy.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "显示详细输出")
+	rootCmd.PersistentFlags().BoolVar(&strictConfig, "strict", false, "配置文件中存在未知的配置项时报错")
 
 	// 添加子命令
 	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(detectCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(artifactsCmd)
+	rootCmd.AddCommand(publishCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(releaseCmd)
+	rootCmd.AddCommand(serviceCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(secretsCmd)
 }
 
 // initConfig 初始化配置
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"deploy/internal/config"
+	"deploy/internal/secrets"
+	"deploy/internal/utils"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	secretsEnv   string
+	keygenAge    bool
+	keygenOutput string
+)
+
+// defaultEditor 未设置 $VISUAL、$EDITOR 时使用的编辑器
+const defaultEditor = "vi"
+
+// secretsCmd 加密配置值命令
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "管理配置文件中的加密值",
+	Long: `加密配置文件中的密码、令牌等敏感值，加密值的格式为 ENC[<算法>,<密文>]。
+
+加载配置时自动解密顶层和当前环境中的加密值，其他环境中的值保持加密。
+解密后的值在所有输出中 (包括 --verbose 输出、命令预览和子进程输出) 显示为 ******。
+
+支持两种密钥：
+- 对称密钥文件 (AES-256-GCM)：默认为 ~/.deploy/secret.key，可通过 secrets.key_file
+  或 DEPLOY_SECRET_KEY 环境变量 (base64 编码的密钥) 指定
+- age：配置 secrets.age_recipients 后使用公钥加密，解密使用 ~/.deploy/age.key，
+  可通过 secrets.age_identity 或 DEPLOY_AGE_IDENTITY 环境变量指定
+
+示例：
+  deploy secrets keygen                              # 生成对称密钥文件
+  deploy secrets encrypt 's3cret'                    # 加密值，输出 ENC[...]
+  echo -n 's3cret' | deploy secrets encrypt          # 从标准输入读取
+  deploy secrets decrypt 'ENC[aes256gcm,...]'        # 解密值
+  deploy secrets edit --env=prod                     # 在编辑器中编辑解密后的配置文件`,
+}
+
+// secretsKeygenCmd 生成密钥
+var secretsKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "生成对称密钥文件或 age 私钥",
+	Args:  cobra.NoArgs,
+	RunE:  runSecretsKeygen,
+}
+
+// secretsEncryptCmd 加密值
+var secretsEncryptCmd = &cobra.Command{
+	Use:   "encrypt [值]",
+	Short: "加密值，未指定时从标准输入读取",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runSecretsEncrypt,
+}
+
+// secretsDecryptCmd 解密值
+var secretsDecryptCmd = &cobra.Command{
+	Use:   "decrypt [值]",
+	Short: "解密 ENC[...] 值，未指定时从标准输入读取",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runSecretsDecrypt,
+}
+
+// secretsEditCmd 编辑配置文件中的加密值
+var secretsEditCmd = &cobra.Command{
+	Use:   "edit [文件]",
+	Short: "在编辑器中编辑解密后的配置文件",
+	Long: `解密配置文件 (默认为查找到的 deploy.yaml) 中的加密值，在 $EDITOR 中打开，保存后重新加密。
+
+解密后的值带有 !secret 标签，新增的敏感值也可以添加 !secret 标签：
+  scripts:
+    variables:
+      DB_PASSWORD: !secret s3cret
+
+未修改的值保留原密文，无法解密的值保持原样。临时文件只有当前用户可读，编辑结束后删除。`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSecretsEdit,
+}
+
+func init() {
+	secretsCmd.PersistentFlags().StringVarP(&secretsEnv, "env", "e", "", "使用指定环境的 secrets 配置")
+	secretsKeygenCmd.Flags().BoolVar(&keygenAge, "age", false, "生成 age 私钥")
+	secretsKeygenCmd.Flags().StringVarP(&keygenOutput, "output", "o", "", "密钥文件路径 (默认为 ~/.deploy/secret.key 或 ~/.deploy/age.key)")
+
+	secretsCmd.AddCommand(secretsKeygenCmd)
+	secretsCmd.AddCommand(secretsEncryptCmd)
+	secretsCmd.AddCommand(secretsDecryptCmd)
+	secretsCmd.AddCommand(secretsEditCmd)
+}
+
+// loadKeyring 根据配置文件中的 secrets 配置创建 Keyring，从 dir 开始查找配置文件，没有配置文件时使用默认密钥
+func loadKeyring(dir string) (*secrets.Keyring, error) {
+	configPath, err := resolveConfigPath(dir)
+	if err != nil {
+		return secrets.New(secrets.Options{}), nil
+	}
+	return config.LoadKeyring(configPath, secretsEnv)
+}
+
+// readSecretInput 获取命令参数或标准输入中的值，去掉末尾的换行
+func readSecretInput(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	data, err := io.ReadAll(bufio.NewReader(os.Stdin))
+	if err != nil {
+		return "", fmt.Errorf("读取标准输入失败: %w", err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("请指定要处理的值")
+	}
+	return value, nil
+}
+
+// runSecretsKeygen 生成密钥文件
+func runSecretsKeygen(cmd *cobra.Command, args []string) error {
+	if keygenAge {
+		path := keygenOutput
+		if path == "" {
+			path = secrets.DefaultIdentityFile()
+		}
+		recipient, err := secrets.GenerateIdentity(path)
+		if err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已生成 age 私钥: %s", path))
+		fmt.Printf("公钥: %s\n", recipient)
+		fmt.Println("将公钥添加到配置文件的 secrets.age_recipients 中")
+		return nil
+	}
+
+	path := keygenOutput
+	if path == "" {
+		path = secrets.DefaultKeyFile()
+	}
+	if err := secrets.GenerateKey(path); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已生成密钥文件: %s", path))
+	fmt.Println("请妥善保存密钥文件，CI 中可将文件内容设置为 DEPLOY_SECRET_KEY 环境变量")
+	return nil
+}
+
+// runSecretsEncrypt 加密值并输出 ENC[...]
+func runSecretsEncrypt(cmd *cobra.Command, args []string) error {
+	value, err := readSecretInput(args)
+	if err != nil {
+		return err
+	}
+	keyring, err := loadKeyring(".")
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := keyring.Encrypt(value)
+	if err != nil {
+		return err
+	}
+	fmt.Println(encrypted)
+	return nil
+}
+
+// runSecretsDecrypt 解密 ENC[...] 值
+func runSecretsDecrypt(cmd *cobra.Command, args []string) error {
+	value, err := readSecretInput(args)
+	if err != nil {
+		return err
+	}
+	keyring, err := loadKeyring(".")
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := keyring.Decrypt(strings.TrimSpace(value))
+	if err != nil {
+		return err
+	}
+	fmt.Println(plaintext)
+	return nil
+}
+
+// runSecretsEdit 解密配置文件后在编辑器中打开，保存后重新加密
+func runSecretsEdit(cmd *cobra.Command, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		configPath, err := resolveConfigPath(".")
+		if err != nil {
+			return err
+		}
+		path = configPath
+	}
+	if config.IsTOML(path) {
+		return fmt.Errorf("仅支持编辑 YAML 配置文件: %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	keyring, err := loadKeyring(".")
+	if err != nil {
+		return err
+	}
+	content, document, err := config.UnsealSecrets(data, keyring)
+	if err != nil {
+		return err
+	}
+	for _, skipped := range document.Skipped {
+		utils.PrintWarning(fmt.Sprintf("无法解密，保持原样: %s", skipped))
+	}
+
+	// 临时文件使用相同的扩展名，编辑器可以识别为 YAML
+	temp, err := os.CreateTemp("", "deploy-secrets-*"+filepath.Ext(path))
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if err := temp.Chmod(0600); err != nil {
+		temp.Close()
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	temp.Close()
+
+	if err := openEditor(temp.Name()); err != nil {
+		return err
+	}
+
+	edited, err := os.ReadFile(temp.Name())
+	if err != nil {
+		return fmt.Errorf("读取临时文件失败: %w", err)
+	}
+	if bytes.Equal(edited, content) {
+		utils.PrintInfo("配置文件未修改")
+		return nil
+	}
+
+	sealed, changed, err := document.Seal(edited)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, sealed, 0644); err != nil {
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	utils.PrintSuccess(fmt.Sprintf("已保存 %s，重新加密 %d 个值", path, changed))
+	return nil
+}
+
+// openEditor 使用 $VISUAL 或 $EDITOR 打开文件，默认为 vi
+func openEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	// 编辑器命令可能带有参数，例如 "code --wait"
+	fields := strings.Fields(editor)
+	editorCmd := exec.Command(fields[0], append(fields[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("编辑器退出失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"deploy/internal/deployer"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var ecosystemOutput string
+
+// serviceCmd 服务管理命令
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "管理已部署的服务",
+	Long: `使用环境配置的服务管理器 (environments.<环境>.service.manager) 管理已部署的服务。
+
+服务管理器：
+- nohup: 使用 default_start_command、default_stop_command、default_status_command (默认)
+- systemd: 根据 java.runtime、service_name、deploy_path 生成 unit 文件，使用 systemctl 管理
+- pm2: 根据环境配置生成 ecosystem.config.js，使用 pm2 startOrReload 管理 Node 服务
+
+示例：
+  deploy service install prod              # 安装服务 (systemd 写入 unit 文件并 daemon-reload)
+  deploy service restart prod              # 重启服务
+  deploy service status prod               # 显示服务状态
+  deploy service ecosystem prod            # 输出 PM2 ecosystem 文件`,
+}
+
+// serviceEcosystemCmd 生成 PM2 ecosystem 文件
+var serviceEcosystemCmd = &cobra.Command{
+	Use:   "ecosystem <环境>",
+	Short: "生成 PM2 ecosystem 文件",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServiceEcosystem,
+}
+
+func init() {
+	actions := []struct {
+		name  string
+		short string
+	}{
+		{"install", "安装服务"},
+		{"start", "启动服务"},
+		{"stop", "停止服务"},
+		{"restart", "重启服务"},
+		{"status", "显示服务状态"},
+	}
+
+	for _, action := range actions {
+		serviceCmd.AddCommand(&cobra.Command{
+			Use:   action.name + " <环境>",
+			Short: action.short,
+			Args:  cobra.ExactArgs(1),
+			RunE:  runService,
+		})
+	}
+
+	serviceEcosystemCmd.Flags().StringVarP(&ecosystemOutput, "output", "o", "", "输出文件 (默认输出到标准输出)")
+	serviceCmd.AddCommand(serviceEcosystemCmd)
+}
+
+// runService 执行服务管理操作
+func runService(cmd *cobra.Command, args []string) error {
+	cfg, err := loadEnvironmentConfig(".", args[0])
+	if err != nil {
+		return err
+	}
+
+	service, err := deployer.NewService(cfg, args[0], "", verbose)
+	if err != nil {
+		return err
+	}
+	manager, err := deployer.NewServiceManager(service)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Name() {
+	case "install":
+		err = manager.Install()
+	case "start":
+		err = manager.Start()
+	case "stop":
+		err = manager.Stop()
+	case "restart":
+		err = manager.Restart()
+	case "status":
+		var status string
+		if status, err = manager.Status(); err == nil {
+			fmt.Printf("⚙️  %s (%s): %s\n", service.Name, manager.Name(), status)
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("%s %s 完成", service.Name, cmd.Name()))
+	return nil
+}
+
+// runServiceEcosystem 生成 PM2 ecosystem 文件
+func runServiceEcosystem(cmd *cobra.Command, args []string) error {
+	cfg, err := loadEnvironmentConfig(".", args[0])
+	if err != nil {
+		return err
+	}
+
+	service, err := deployer.NewService(cfg, args[0], "npm", verbose)
+	if err != nil {
+		return err
+	}
+	content, err := service.RenderEcosystem()
+	if err != nil {
+		return err
+	}
+
+	if ecosystemOutput == "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := service.WriteEcosystemEnv(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(ecosystemOutput, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入 ecosystem 文件失败: %w", err)
+	}
+	utils.PrintSuccess(fmt.Sprintf("已生成 PM2 ecosystem 文件: %s", ecosystemOutput))
+	if len(service.Env.Scripts.Variables) > 0 {
+		utils.PrintInfo(fmt.Sprintf("scripts.variables 已写入 %s (权限 0600)", service.EcosystemEnvPath()))
+	}
+	return nil
+}
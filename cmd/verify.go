@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// verifyCmd 校验命令
+var verifyCmd = &cobra.Command{
+	Use:   "verify <构建产物>",
+	Short: "校验构建产物",
+	Long: `根据构建时生成的 .sha256 文件校验构建产物是否完整。
+
+如果存在构建清单 (.manifest.json)，会同时显示清单中的构建信息。
+
+示例：
+  deploy verify ./build/my-app-1.0.0.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVerify,
+}
+
+// runVerify 执行校验
+func runVerify(cmd *cobra.Command, args []string) error {
+	artifactPath := args[0]
+
+	if !utils.FileExists(artifactPath) {
+		return fmt.Errorf("构建产物不存在: %s", artifactPath)
+	}
+
+	fmt.Printf("🔐 校验构建产物: %s\n", artifactPath)
+
+	if err := manifest.VerifyChecksum(artifactPath); err != nil {
+		utils.PrintError(fmt.Sprintf("校验失败: %v", err))
+		return err
+	}
+
+	utils.PrintSuccess("校验和匹配")
+
+	// 显示构建清单信息
+	manifestPath := manifest.ManifestPath(artifactPath)
+	if !utils.FileExists(manifestPath) {
+		return nil
+	}
+
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		utils.PrintWarning(fmt.Sprintf("读取构建清单失败: %v", err))
+		return nil
+	}
+
+	fmt.Println("\n📋 构建清单:")
+	fmt.Printf("  项目名称: %s\n", m.Project)
+	fmt.Printf("  版本号: %s\n", m.Version)
+	fmt.Printf("  构建器: %s\n", m.Builder)
+	fmt.Printf("  构建时间: %s\n", m.Timestamp)
+	fmt.Printf("  构建主机: %s\n", m.Host)
+	if m.Git != nil {
+		dirty := ""
+		if m.Git.Dirty {
+			dirty = " (有未提交的修改)"
+		}
+		fmt.Printf("  Git 提交: %s@%s%s\n", m.Git.Branch, m.Git.Commit, dirty)
+	}
+
+	return nil
+}
@@ -0,0 +1,495 @@
+package builder
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"deploy/internal/manifest"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 构建产物格式
+const (
+	FormatTarGz  = "tar.gz"
+	FormatTarZst = "tar.zst"
+	FormatZip    = "zip"
+	FormatDir    = "dir"
+	FormatJar    = "jar" // 仅复制 JAR 文件，Java 项目的默认格式
+)
+
+// DefaultCompressionLevel 未配置 compression_level 时的压缩级别，由各格式使用自己的默认级别
+const DefaultCompressionLevel = -1
+
+// archiveEntry 待打包的文件
+type archiveEntry struct {
+	Name    string      // 压缩包内的路径（使用 / 分隔）
+	Path    string      // 磁盘上的源文件路径，为空时使用 Data
+	Data    []byte      // 生成的文件内容
+	Link    string      // 符号链接的目标
+	Mode    os.FileMode // 文件权限，目录包含 os.ModeDir，符号链接包含 os.ModeSymlink
+	Size    int64
+	ModTime time.Time
+}
+
+// archiveOptions 打包选项
+type archiveOptions struct {
+	Format           string
+	CompressionLevel int // DefaultCompressionLevel 表示默认级别
+	Reproducible     bool
+	ModTime          time.Time // 可重现模式下统一使用的时间戳
+}
+
+// archiveWriter 构建产物写入器
+type archiveWriter interface {
+	// Create 创建条目，目录返回 nil
+	Create(entry archiveEntry) (io.Writer, error)
+	// Close 完成写入
+	Close() error
+}
+
+// IsValidArtifactFormat 检查构建产物格式是否有效
+func IsValidArtifactFormat(format string) bool {
+	switch format {
+	case FormatTarGz, FormatTarZst, FormatZip, FormatDir, FormatJar:
+		return true
+	}
+	return false
+}
+
+// artifactExtension 获取构建产物的扩展名
+func artifactExtension(format string) string {
+	if format == FormatDir {
+		return ""
+	}
+	return "." + format
+}
+
+// fileEntry 根据磁盘文件创建条目
+func fileEntry(path, name string, info os.FileInfo) archiveEntry {
+	entry := archiveEntry{
+		Name:    name,
+		Path:    path,
+		Mode:    info.Mode() & (os.ModeDir | os.ModeSymlink | os.ModePerm),
+		ModTime: info.ModTime(),
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		// 保留符号链接本身（例如 node_modules/.bin）
+		if link, err := os.Readlink(path); err == nil {
+			entry.Link = link
+		} else {
+			entry.Mode &^= os.ModeSymlink
+			entry.Size = info.Size()
+		}
+	case !info.IsDir():
+		entry.Size = info.Size()
+	}
+
+	return entry
+}
+
+// isSymlink 是否为符号链接条目
+func (e archiveEntry) isSymlink() bool {
+	return e.Mode&os.ModeSymlink != 0
+}
+
+// dataEntry 根据生成的内容创建条目
+func dataEntry(name string, data []byte, mode os.FileMode) archiveEntry {
+	return archiveEntry{
+		Name:    name,
+		Data:    data,
+		Mode:    mode,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+}
+
+// collectDirEntries 收集目录下的所有文件，prefix 为压缩包内的路径前缀
+func collectDirEntries(root, prefix string) ([]archiveEntry, error) {
+	var entries []archiveEntry
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// 计算相对路径
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		// 跳过根目录
+		if relPath == "." {
+			return nil
+		}
+
+		entries = append(entries, fileEntry(path, joinArchivePath(prefix, filepath.ToSlash(relPath)), info))
+		return nil
+	})
+
+	return entries, err
+}
+
+// joinArchivePath 拼接压缩包内路径
+func joinArchivePath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + name
+}
+
+// sortArchiveEntries 按压缩包内路径排序
+func sortArchiveEntries(entries []archiveEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// writeArtifact 将条目写入构建产物，返回文件列表、每个文件的校验和以及产物大小
+func writeArtifact(artifactPath string, entries []archiveEntry, opts archiveOptions) ([]string, []manifest.FileChecksum, int64, error) {
+	writer, err := newArchiveWriter(artifactPath, opts)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	sortArchiveEntries(entries)
+
+	var files []string
+	var checksums []manifest.FileChecksum
+	var contentSize int64
+
+	for _, entry := range entries {
+		if opts.Reproducible {
+			normalizeEntry(&entry, opts.ModTime)
+		}
+		files = append(files, entry.Name)
+
+		w, err := writer.Create(entry)
+		if err != nil {
+			writer.Close()
+			return nil, nil, 0, fmt.Errorf("写入 %s 失败: %w", entry.Name, err)
+		}
+		if w == nil {
+			continue
+		}
+
+		sum, size, err := copyEntryWithChecksum(w, entry)
+		if err != nil {
+			writer.Close()
+			return nil, nil, 0, fmt.Errorf("写入 %s 失败: %w", entry.Name, err)
+		}
+		contentSize += size
+
+		checksums = append(checksums, manifest.FileChecksum{
+			Path:   entry.Name,
+			Size:   size,
+			SHA256: sum,
+		})
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, nil, 0, fmt.Errorf("完成构建产物写入失败: %w", err)
+	}
+
+	// 目录格式的大小为所有文件大小之和
+	if opts.Format == FormatDir {
+		return files, checksums, contentSize, nil
+	}
+
+	stat, err := os.Stat(artifactPath)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	return files, checksums, stat.Size(), nil
+}
+
+// copyEntryWithChecksum 将条目内容写入 w，同时计算 SHA-256
+func copyEntryWithChecksum(w io.Writer, entry archiveEntry) (string, int64, error) {
+	if entry.Path == "" {
+		return copyWithChecksum(w, bytes.NewReader(entry.Data))
+	}
+
+	file, err := os.Open(entry.Path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	return copyWithChecksum(w, file)
+}
+
+// newArchiveWriter 根据格式创建写入器
+func newArchiveWriter(artifactPath string, opts archiveOptions) (archiveWriter, error) {
+	switch opts.Format {
+	case FormatTarGz:
+		return newTarGzWriter(artifactPath, opts)
+	case FormatTarZst:
+		return newTarZstWriter(artifactPath, opts)
+	case FormatZip:
+		return newZipWriter(artifactPath, opts)
+	case FormatDir:
+		return newDirWriter(artifactPath)
+	default:
+		return nil, fmt.Errorf("不支持的构建产物格式: %s", opts.Format)
+	}
+}
+
+// tarWriter tar 格式写入器
+type tarWriter struct {
+	tw      *tar.Writer
+	closers []io.Closer
+}
+
+// Create 写入 tar header
+func (t *tarWriter) Create(entry archiveEntry) (io.Writer, error) {
+	header := &tar.Header{
+		Name:     entry.Name,
+		Mode:     int64(entry.Mode.Perm()),
+		ModTime:  entry.ModTime,
+		Typeflag: tar.TypeReg,
+		Size:     entry.Size,
+	}
+	switch {
+	case entry.Mode.IsDir():
+		header.Name += "/"
+		header.Typeflag = tar.TypeDir
+		header.Size = 0
+	case entry.isSymlink():
+		header.Typeflag = tar.TypeSymlink
+		header.Linkname = entry.Link
+		header.Size = 0
+	}
+
+	if err := t.tw.WriteHeader(header); err != nil {
+		return nil, err
+	}
+
+	if header.Typeflag != tar.TypeReg {
+		return nil, nil
+	}
+	return t.tw, nil
+}
+
+// Close 依次关闭 tar、压缩流和文件
+func (t *tarWriter) Close() error {
+	err := t.tw.Close()
+	for _, c := range t.closers {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// newTarGzWriter 创建 tar.gz 写入器
+func newTarGzWriter(artifactPath string, opts archiveOptions) (archiveWriter, error) {
+	level := opts.CompressionLevel
+	if level == DefaultCompressionLevel {
+		level = gzip.DefaultCompression
+	} else if level < gzip.NoCompression || level > gzip.BestCompression {
+		return nil, fmt.Errorf("gzip 压缩级别无效: %d (0-9)", level)
+	}
+
+	file, err := os.Create(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("创建压缩文件失败: %w", err)
+	}
+
+	gzWriter, err := gzip.NewWriterLevel(file, level)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("gzip 压缩级别无效: %d", opts.CompressionLevel)
+	}
+	if opts.Reproducible {
+		normalizeGzipHeader(&gzWriter.Header)
+	}
+
+	return &tarWriter{
+		tw:      tar.NewWriter(gzWriter),
+		closers: []io.Closer{gzWriter, file},
+	}, nil
+}
+
+// zstdProcess 通过 zstd 命令压缩
+type zstdProcess struct {
+	stdin io.WriteCloser
+	cmd   *exec.Cmd
+}
+
+// Close 关闭输入并等待 zstd 退出
+func (z *zstdProcess) Close() error {
+	if err := z.stdin.Close(); err != nil {
+		return err
+	}
+	if err := z.cmd.Wait(); err != nil {
+		return fmt.Errorf("zstd 压缩失败: %w", err)
+	}
+	return nil
+}
+
+// newTarZstWriter 创建 tar.zst 写入器，压缩由系统的 zstd 命令完成
+func newTarZstWriter(artifactPath string, opts archiveOptions) (archiveWriter, error) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return nil, fmt.Errorf("tar.zst 格式需要 zstd 命令，但未安装或不在 PATH 中")
+	}
+
+	args := []string{"-q", "-f", "-o", artifactPath}
+	if opts.CompressionLevel != DefaultCompressionLevel {
+		if opts.CompressionLevel < 1 || opts.CompressionLevel > 19 {
+			return nil, fmt.Errorf("zstd 压缩级别无效: %d (1-19)", opts.CompressionLevel)
+		}
+		args = append([]string{fmt.Sprintf("-%d", opts.CompressionLevel)}, args...)
+	}
+
+	cmd := exec.Command("zstd", args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("启动 zstd 失败: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("启动 zstd 失败: %w", err)
+	}
+
+	return &tarWriter{
+		tw:      tar.NewWriter(stdin),
+		closers: []io.Closer{&zstdProcess{stdin: stdin, cmd: cmd}},
+	}, nil
+}
+
+// zipWriter zip 格式写入器
+type zipWriter struct {
+	zw   *zip.Writer
+	file *os.File
+}
+
+// newZipWriter 创建 zip 写入器
+func newZipWriter(artifactPath string, opts archiveOptions) (archiveWriter, error) {
+	level := opts.CompressionLevel
+	if level == DefaultCompressionLevel {
+		level = flate.DefaultCompression
+	} else if level < flate.NoCompression || level > flate.BestCompression {
+		return nil, fmt.Errorf("zip 压缩级别无效: %d (0-9)", level)
+	}
+
+	file, err := os.Create(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("创建压缩文件失败: %w", err)
+	}
+
+	zw := zip.NewWriter(file)
+	zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(out, level)
+	})
+
+	return &zipWriter{zw: zw, file: file}, nil
+}
+
+// Create 创建 zip 条目
+func (z *zipWriter) Create(entry archiveEntry) (io.Writer, error) {
+	header := &zip.FileHeader{
+		Name:     entry.Name,
+		Method:   zip.Deflate,
+		Modified: entry.ModTime,
+	}
+	header.SetMode(entry.Mode)
+
+	switch {
+	case entry.Mode.IsDir():
+		header.Name += "/"
+		header.Method = zip.Store
+		_, err := z.zw.CreateHeader(header)
+		return nil, err
+	case entry.isSymlink():
+		// zip 中的符号链接以链接目标作为文件内容
+		header.Method = zip.Store
+		w, err := z.zw.CreateHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.WriteString(w, entry.Link)
+		return nil, err
+	}
+
+	return z.zw.CreateHeader(header)
+}
+
+// Close 关闭 zip 和文件
+func (z *zipWriter) Close() error {
+	err := z.zw.Close()
+	if cerr := z.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// dirWriter 目录格式写入器
+type dirWriter struct {
+	root    string
+	current *os.File
+}
+
+// newDirWriter 创建目录写入器，已存在的同名目录会被替换
+func newDirWriter(artifactPath string) (archiveWriter, error) {
+	if err := os.RemoveAll(artifactPath); err != nil {
+		return nil, fmt.Errorf("清理输出目录失败: %w", err)
+	}
+	if err := os.MkdirAll(artifactPath, 0755); err != nil {
+		return nil, fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	return &dirWriter{root: artifactPath}, nil
+}
+
+// Create 创建目录或文件
+func (d *dirWriter) Create(entry archiveEntry) (io.Writer, error) {
+	if err := d.closeCurrent(); err != nil {
+		return nil, err
+	}
+
+	target := filepath.Join(d.root, filepath.FromSlash(entry.Name))
+	if entry.Mode.IsDir() {
+		return nil, os.MkdirAll(target, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nil, err
+	}
+
+	if entry.isSymlink() {
+		return nil, os.Symlink(entry.Link, target)
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, entry.Mode.Perm())
+	if err != nil {
+		return nil, err
+	}
+	d.current = file
+	return file, nil
+}
+
+// Close 关闭最后一个文件
+func (d *dirWriter) Close() error {
+	return d.closeCurrent()
+}
+
+// closeCurrent 关闭当前正在写入的文件
+func (d *dirWriter) closeCurrent() error {
+	if d.current == nil {
+		return nil
+	}
+	err := d.current.Close()
+	d.current = nil
+	return err
+}
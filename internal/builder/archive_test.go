@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"deploy/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// artifactSize 使用指定的 compression_level 打包可压缩的内容，返回构建产物大小
+func artifactSize(t *testing.T, format string, level *int) int64 {
+	t.Helper()
+
+	cfg := &config.Config{Artifact: config.ArtifactConfig{Format: format, CompressionLevel: level}}
+	opts, err := resolveArchiveOptions(cfg, &BuildOptions{}, FormatTarGz, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []archiveEntry{dataEntry("data.txt", []byte(strings.Repeat("deploy ", 10000)), 0644)}
+	artifactPath := filepath.Join(t.TempDir(), "app"+artifactExtension(format))
+	if _, _, _, err := writeArtifact(artifactPath, entries, opts); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(artifactPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func TestCompressionLevelZero(t *testing.T) {
+	zero := 0
+	for _, format := range []string{FormatTarGz, FormatZip} {
+		t.Run(format, func(t *testing.T) {
+			stored := artifactSize(t, format, &zero)
+			compressed := artifactSize(t, format, nil)
+			// 70000 字节的重复内容，只存储时不小于原始大小，默认级别压缩后远小于原始大小
+			if stored < 70000 {
+				t.Errorf("compression_level 为 0 时不应压缩: %d 字节", stored)
+			}
+			if compressed >= 70000 {
+				t.Errorf("未设置 compression_level 时应使用默认级别压缩: %d 字节", compressed)
+			}
+		})
+	}
+}
+
+func TestCompressionLevelInvalid(t *testing.T) {
+	level := 10
+	cfg := &config.Config{Artifact: config.ArtifactConfig{Format: FormatTarGz, CompressionLevel: &level}}
+	opts, err := resolveArchiveOptions(cfg, &BuildOptions{}, FormatTarGz, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifactPath := filepath.Join(t.TempDir(), "app.tar.gz")
+	if _, _, _, err := writeArtifact(artifactPath, nil, opts); err == nil {
+		t.Fatal("gzip 压缩级别 10 应返回错误")
+	}
+}
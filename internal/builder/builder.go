@@ -3,6 +3,9 @@ package builder
 import (
 	"deploy/internal/config"
 	"deploy/internal/detector"
+	"deploy/internal/store"
+	"deploy/internal/utils"
+	"fmt"
 )
 
 // Builder 构建器接口
@@ -13,27 +16,42 @@ type Builder interface {
 	GetType() detector.ProjectType
 	// Validate 验证构建环境
 	Validate() error
+	// Toolchain 获取工具链版本
+	Toolchain() map[string]string
 }
 
 // BuildResult 构建结果
 type BuildResult struct {
 	Success      bool     `json:"success"`
+	Builder      string   `json:"builder"`
 	ArtifactPath string   `json:"artifact_path"`
 	Version      string   `json:"version"`
 	BuildTime    string   `json:"build_time"`
 	Files        []string `json:"files"`
 	Size         int64    `json:"size"`
 	Message      string   `json:"message"`
+	Checksum     string   `json:"checksum"`
+	ManifestPath string   `json:"manifest_path"`
+	ChecksumPath string   `json:"checksum_path"`
+	CacheHit     bool     `json:"cache_hit"`
+	CacheKey     string   `json:"cache_key,omitempty"`
+	Image        string   `json:"image,omitempty"` // docker 构建器生成的镜像
+	ImageID      string   `json:"image_id,omitempty"`
+	ImageDigest  string   `json:"image_digest,omitempty"` // 推送后镜像仓库返回的摘要
 }
 
 // BuildOptions 构建选项
 type BuildOptions struct {
-	ProjectPath string
-	Environment string
-	OutputPath  string
-	Version     string
-	Verbose     bool
-	SkipTests   bool
+	ProjectType  detector.ProjectType // 为空时自动检测
+	ProjectPath  string
+	Environment  string
+	OutputPath   string
+	Version      string
+	Verbose      bool
+	SkipTests    bool
+	Reproducible bool
+	Store        *store.Store // 构建缓存使用的本地仓库，为 nil 时不使用缓存
+	NoCache      bool         // 忽略缓存强制重新构建，构建结果仍会记录缓存键
 }
 
 // NewBuilder 创建构建器
@@ -45,6 +63,12 @@ func NewBuilder(projectType detector.ProjectType, config *config.Config, options
 		return NewMavenBuilder(config, options), nil
 	case detector.ProjectTypeGradle:
 		return NewGradleBuilder(config, options), nil
+	case detector.ProjectTypeGo:
+		return NewGoBuilder(config, options), nil
+	case detector.ProjectTypePython:
+		return NewPythonBuilder(config, options), nil
+	case detector.ProjectTypeDocker:
+		return NewDockerBuilder(config, options), nil
 	default:
 		return nil, ErrUnsupportedProjectType
 	}
@@ -53,13 +77,17 @@ func NewBuilder(projectType detector.ProjectType, config *config.Config, options
 // BuildProject 构建项目的便捷函数
 func BuildProject(config *config.Config, options *BuildOptions) (*BuildResult, error) {
 	// 检测项目类型
-	projectInfo, err := detector.DetectProject(options.ProjectPath)
-	if err != nil {
-		return nil, err
+	projectType := options.ProjectType
+	if projectType == "" {
+		projectInfo, err := detector.DetectProject(options.ProjectPath)
+		if err != nil {
+			return nil, err
+		}
+		projectType = projectInfo.Type
 	}
 
 	// 创建构建器
-	builder, err := NewBuilder(projectInfo.Type, config, options)
+	builder, err := NewBuilder(projectType, config, options)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +97,28 @@ func BuildProject(config *config.Config, options *BuildOptions) (*BuildResult, e
 		return nil, err
 	}
 
+	// 检查构建缓存
+	var cacheKey string
+	if options.Store != nil {
+		cacheKey, err = computeCacheKey(config, options, builder)
+		if err != nil {
+			utils.PrintWarning(fmt.Sprintf("计算缓存键失败，不使用构建缓存: %v", err))
+		} else if !options.NoCache {
+			result, err := restoreFromCache(config, options, cacheKey)
+			if err != nil {
+				utils.PrintWarning(fmt.Sprintf("读取构建缓存失败: %v", err))
+			} else if result != nil {
+				fmt.Printf("♻️  命中构建缓存，复用版本 %s\n", result.Version)
+				return result, nil
+			}
+		}
+	}
+
 	// 执行构建
-	return builder.Build()
+	result, err := builder.Build()
+	if result != nil {
+		result.Builder = string(builder.GetType())
+		result.CacheKey = cacheKey
+	}
+	return result, err
 }
@@ -0,0 +1,199 @@
+package builder
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// usesJavaArchive 是否需要将 Java 构建产物打包为压缩包，否则直接复制 JAR 文件
+func usesJavaArchive(cfg *config.Config) bool {
+	format := cfg.Artifact.Format
+	return cfg.Artifact.Bundle.Enabled || hasArtifactSelection(cfg) || (format != "" && format != FormatJar)
+}
+
+// configuredJavaArtifacts 按 java.artifact_path 查找构建产物 (例如 WAR 项目的 target/*.war)，
+// 未配置或未匹配到文件时返回空，由构建工具在默认目录中查找 JAR 文件
+func configuredJavaArtifacts(cfg *config.Config) ([]string, error) {
+	if cfg.Java.ArtifactPath == "" {
+		return nil, nil
+	}
+	matches, err := filepath.Glob(filepath.FromSlash(cfg.Java.ArtifactPath))
+	if err != nil {
+		return nil, fmt.Errorf("java.artifact_path 无效 %q: %w", cfg.Java.ArtifactPath, err)
+	}
+	return matches, nil
+}
+
+// packageJavaArchive 将 JAR 文件（以及部署包中的脚本和配置）写入构建产物
+func packageJavaArchive(cfg *config.Config, options *BuildOptions, mainJar, outputDir string) (string, []string, []manifest.FileChecksum, int64, error) {
+	if cfg.Artifact.Format == FormatJar {
+		return "", nil, nil, 0, fmt.Errorf("jar 格式不支持部署包和额外文件，请将 artifact.format 设置为 tar.gz、tar.zst、zip 或 dir")
+	}
+
+	opts, err := resolveArchiveOptions(cfg, options, FormatTarGz, options.Reproducible)
+	if err != nil {
+		return "", nil, nil, 0, err
+	}
+
+	entries, err := javaArchiveEntries(cfg, options, mainJar)
+	if err != nil {
+		return "", nil, nil, 0, err
+	}
+	entries, err = applyArtifactSelection(cfg, options.ProjectPath, entries)
+	if err != nil {
+		return "", nil, nil, 0, err
+	}
+
+	artifactName := fmt.Sprintf("%s-%s%s", cfg.Project.Name, options.Version, artifactExtension(opts.Format))
+	artifactPath := filepath.Join(outputDir, artifactName)
+
+	files, checksums, size, err := writeArtifact(artifactPath, entries, opts)
+	if err != nil {
+		return "", nil, nil, 0, err
+	}
+
+	return artifactPath, files, checksums, size, nil
+}
+
+// javaArchiveEntries 组装 Java 构建产物的条目
+//
+// 部署包结构：
+//
+//	<name>.jar 或 <name>.war
+//	bin/       启动脚本
+//	config/    配置文件和 application.yml
+func javaArchiveEntries(cfg *config.Config, options *BuildOptions, mainJar string) ([]archiveEntry, error) {
+	jarName := cfg.Project.Name + filepath.Ext(mainJar)
+
+	info, err := os.Stat(mainJar)
+	if err != nil {
+		return nil, fmt.Errorf("获取 JAR 文件信息失败: %w", err)
+	}
+	entries := []archiveEntry{fileEntry(mainJar, jarName, info)}
+
+	bundle := cfg.Artifact.Bundle
+	if !bundle.Enabled {
+		return entries, nil
+	}
+
+	// 启动脚本
+	if len(bundle.Scripts) > 0 {
+		for _, script := range bundle.Scripts {
+			path := resolveProjectFile(options.ProjectPath, script)
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, fmt.Errorf("启动脚本不存在: %s", script)
+			}
+			entry := fileEntry(path, "bin/"+filepath.Base(path), info)
+			entry.Mode |= 0111
+			entries = append(entries, entry)
+		}
+	} else {
+		scripts, err := generateJavaScripts(cfg, jarName)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, scripts...)
+	}
+
+	// 配置文件
+	for _, configFile := range bundle.ConfigFiles {
+		path := resolveProjectFile(options.ProjectPath, configFile)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("配置文件不存在: %s", configFile)
+		}
+
+		if !info.IsDir() {
+			entries = append(entries, fileEntry(path, "config/"+filepath.Base(path), info))
+			continue
+		}
+
+		// 目录中的内容直接放入 config/
+		dirEntries, err := collectDirEntries(path, "config")
+		if err != nil {
+			return nil, fmt.Errorf("收集配置目录失败: %w", err)
+		}
+		entries = append(entries, dirEntries...)
+	}
+
+	// 渲染 application.yml
+	if bundle.ApplicationTemplate != "" {
+		content, err := renderApplicationConfig(cfg, options, bundle.ApplicationTemplate)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, dataEntry("config/application.yml", []byte(content), 0644))
+	}
+
+	return entries, nil
+}
+
+// generateJavaScripts 根据默认启动/停止命令生成 bin/start.sh 和 bin/stop.sh
+func generateJavaScripts(cfg *config.Config, jarName string) ([]archiveEntry, error) {
+	data := cfg.Java.CommandData(jarName, fmt.Sprintf("logs/%s.log", cfg.Project.Name), cfg.Project.Name+".pid")
+
+	commands := []struct {
+		name     string
+		template string
+	}{
+		{"start.sh", cfg.Java.DefaultStartCommand},
+		{"stop.sh", cfg.Java.DefaultStopCommand},
+	}
+
+	if cfg.Java.DefaultStartCommand == "" {
+		utils.PrintWarning("未配置 java.default_start_command，部署包中不包含启动脚本")
+	}
+
+	var entries []archiveEntry
+	for _, c := range commands {
+		if c.template == "" {
+			continue
+		}
+
+		command, err := config.RenderTemplate(c.name, c.template, data)
+		if err != nil {
+			return nil, err
+		}
+
+		script := fmt.Sprintf("#!/bin/sh\n# 由 deploy 生成\ncd \"$(dirname \"$0\")/..\" || exit 1\nmkdir -p logs\n%s\n", command)
+		entries = append(entries, dataEntry("bin/"+c.name, []byte(script), 0755))
+	}
+
+	return entries, nil
+}
+
+// applicationTemplateData application.yml 模板变量
+type applicationTemplateData struct {
+	Project     string
+	Version     string
+	Environment string
+	Variables   map[string]string
+	Config      *config.Config
+}
+
+// renderApplicationConfig 渲染 application.yml 模板
+func renderApplicationConfig(cfg *config.Config, options *BuildOptions, templatePath string) (string, error) {
+	path := resolveProjectFile(options.ProjectPath, templatePath)
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取 application.yml 模板失败: %w", err)
+	}
+
+	data := applicationTemplateData{
+		Project:     cfg.Project.Name,
+		Version:     options.Version,
+		Environment: options.Environment,
+		Variables:   map[string]string{},
+		Config:      cfg,
+	}
+	if env, ok := cfg.Environments[options.Environment]; ok && env.Scripts.Variables != nil {
+		data.Variables = env.Scripts.Variables
+	}
+
+	return config.RenderTemplate(filepath.Base(path), string(text), data)
+}
@@ -0,0 +1,292 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"deploy/internal/config"
+	"deploy/internal/detector"
+	"deploy/internal/manifest"
+	"deploy/internal/store"
+	"deploy/internal/utils"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// cacheKeyVersion 缓存键格式版本，计算方式变化时需要修改
+const cacheKeyVersion = "v1"
+
+// cacheKeyInput 参与计算缓存键的配置
+type cacheKeyInput struct {
+	Version      string
+	Builder      string
+	Toolchain    map[string]string
+	Build        interface{}
+	Artifact     config.ArtifactConfig
+	SkipTests    bool
+	Reproducible bool
+	BuildVersion string // 只有显式指定版本号时才参与计算
+	Sources      string
+}
+
+// computeCacheKey 根据源文件、依赖锁文件、构建配置和工具链版本计算缓存键
+func computeCacheKey(cfg *config.Config, options *BuildOptions, builder Builder) (string, error) {
+	sources, err := hashSources(cfg, options, builder.GetType())
+	if err != nil {
+		return "", err
+	}
+
+	input := cacheKeyInput{
+		Version:      cacheKeyVersion,
+		Builder:      string(builder.GetType()),
+		Toolchain:    builder.Toolchain(),
+		Artifact:     cfg.Artifact,
+		SkipTests:    options.SkipTests,
+		Reproducible: options.Reproducible,
+		BuildVersion: options.Version,
+		Sources:      sources,
+	}
+	// 仓库配置与构建产物内容无关
+	input.Artifact.Store = config.StoreConfig{}
+
+	switch builder.GetType() {
+	case detector.ProjectTypeNPM:
+		input.Build = cfg.NPM
+	case detector.ProjectTypeGo:
+		input.Build = cfg.Go
+	case detector.ProjectTypePython:
+		input.Build = cfg.Python
+	case detector.ProjectTypeDocker:
+		// 没有 Dockerfile 时会先构建项目
+		input.Build = []interface{}{cfg.Docker, cfg.NPM, cfg.Java}
+	default:
+		input.Build = cfg.Java
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("序列化缓存键失败: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// hashSources 计算项目源文件的校验和
+//
+// Git 项目使用已跟踪和未被忽略的文件，其他项目遍历整个目录。
+// 依赖目录、构建输出目录不参与计算。
+func hashSources(cfg *config.Config, options *BuildOptions, projectType detector.ProjectType) (string, error) {
+	files, err := utils.GitListFiles(options.ProjectPath)
+	if err != nil {
+		files, err = walkSourceFiles(options.ProjectPath)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	excludes := cacheExcludes(cfg, options, projectType)
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, file := range files {
+		file = filepath.ToSlash(file)
+		if isCacheExcluded(file, excludes) {
+			continue
+		}
+
+		sum, err := hashSourceFile(filepath.Join(options.ProjectPath, filepath.FromSlash(file)))
+		if errors.Is(err, os.ErrNotExist) {
+			// 已删除但尚未提交的文件
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("计算 %s 校验和失败: %w", file, err)
+		}
+		fmt.Fprintf(h, "%s\x00%s\n", file, sum)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashSourceFile 计算单个文件的校验和，符号链接使用链接目标
+func hashSourceFile(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		io.WriteString(h, "symlink:"+link)
+	case info.IsDir():
+		// Git 子模块
+		io.WriteString(h, "dir")
+	default:
+		file, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		fmt.Fprintf(h, "%o:", info.Mode().Perm())
+		if _, err := io.Copy(h, file); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// walkSourceFiles 遍历非 Git 项目的所有文件
+func walkSourceFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if name := info.Name(); path != root && (name == ".git" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relPath))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("遍历项目文件失败: %w", err)
+	}
+	return files, nil
+}
+
+// cacheExcludes 获取不参与计算的目录（相对项目目录），只排除对应构建工具生成的目录和输出目录
+//
+// 其他构建工具的输出目录名 (例如 dist、build) 在 Go、Python 项目中可能是源码，仍参与计算。
+func cacheExcludes(cfg *config.Config, options *BuildOptions, projectType detector.ProjectType) []string {
+	npmBuildDir := cfg.NPM.BuildDir
+	if npmBuildDir == "" {
+		npmBuildDir = "dist"
+	}
+
+	var excludes []string
+	switch projectType {
+	case detector.ProjectTypeNPM:
+		excludes = append(excludes, npmBuildDir)
+	case detector.ProjectTypeMaven:
+		excludes = append(excludes, "target")
+	case detector.ProjectTypeGradle:
+		excludes = append(excludes, "build", ".gradle")
+	case detector.ProjectTypePython:
+		excludes = append(excludes, "dist")
+	case detector.ProjectTypeDocker:
+		// 没有 Dockerfile 时会先构建项目
+		excludes = append(excludes, "target", "build", ".gradle", npmBuildDir)
+	}
+
+	outputDir := options.OutputPath
+	if outputDir == "" {
+		outputDir = "build"
+	}
+	if filepath.IsAbs(outputDir) {
+		relPath, err := filepath.Rel(options.ProjectPath, outputDir)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			return excludes
+		}
+		outputDir = relPath
+	}
+
+	return append(excludes, outputDir)
+}
+
+// isCacheExcluded 判断文件是否在排除的目录中
+func isCacheExcluded(file string, excludes []string) bool {
+	for _, segment := range strings.Split(file, "/") {
+		if segment == ".git" || segment == "node_modules" {
+			return true
+		}
+	}
+
+	for _, exclude := range excludes {
+		exclude = strings.Trim(filepath.ToSlash(filepath.Clean(exclude)), "/")
+		if exclude == "" || exclude == "." {
+			continue
+		}
+		if file == exclude || strings.HasPrefix(file, exclude+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreFromCache 从本地仓库复制缓存键相同的构建产物，未命中时返回 nil
+func restoreFromCache(cfg *config.Config, options *BuildOptions, cacheKey string) (*BuildResult, error) {
+	record, err := options.Store.FindByCacheKey(cfg.Project.Name, cacheKey)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 仓库中的构建产物被修改时不使用缓存
+	if err := manifest.VerifyChecksum(options.Store.ArtifactPath(record)); err != nil {
+		utils.PrintWarning(fmt.Sprintf("缓存的构建产物 %s 校验失败，重新构建: %v", record.Version, err))
+		return nil, nil
+	}
+
+	outputDir := options.OutputPath
+	if outputDir == "" {
+		outputDir = "build"
+	}
+	outputDir = resolveProjectFile(options.ProjectPath, outputDir)
+
+	artifactPath, err := options.Store.Export(record, outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BuildResult{
+		Success:      true,
+		Builder:      record.Builder,
+		ArtifactPath: artifactPath,
+		Version:      record.Version,
+		BuildTime:    record.BuildTime,
+		Files:        record.Files,
+		Size:         record.Size,
+		Message:      fmt.Sprintf("使用缓存的构建产物 %s", record.Version),
+		Checksum:     record.Checksum,
+		ManifestPath: manifest.ManifestPath(artifactPath),
+		ChecksumPath: manifest.ChecksumPath(artifactPath),
+		CacheHit:     true,
+		CacheKey:     cacheKey,
+	}
+
+	// docker 构建器的镜像信息保存在镜像描述文件中
+	if strings.HasSuffix(artifactPath, ".image.json") {
+		if data, err := os.ReadFile(artifactPath); err == nil {
+			var descriptor imageDescriptor
+			if json.Unmarshal(data, &descriptor) == nil {
+				result.Image = descriptor.Image
+				result.ImageID = descriptor.ID
+				result.ImageDigest = descriptor.Digest
+			}
+		}
+	}
+
+	return result, nil
+}
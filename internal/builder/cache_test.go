@@ -0,0 +1,129 @@
+package builder
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/detector"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsCacheExcluded(t *testing.T) {
+	tests := []struct {
+		file     string
+		excludes []string
+		expected bool
+	}{
+		{file: "src/index.js", excludes: []string{"dist", "build"}, expected: false},
+		{file: "dist/index.js", excludes: []string{"dist", "build"}, expected: true},
+		{file: "dist", excludes: []string{"dist/"}, expected: true},
+		{file: "distribution/a.txt", excludes: []string{"dist"}, expected: false},
+		{file: "web/dist/a.js", excludes: []string{"./web/dist"}, expected: true},
+		{file: "web/dist/a.js", excludes: []string{"dist"}, expected: false},
+		{file: "packages/app/node_modules/a/index.js", expected: true},
+		{file: "sub/.git/config", expected: true},
+		{file: "main.go", excludes: []string{"", "."}, expected: false},
+	}
+
+	for _, tt := range tests {
+		if actual := isCacheExcluded(tt.file, tt.excludes); actual != tt.expected {
+			t.Errorf("isCacheExcluded(%q, %q) = %v，应为 %v", tt.file, tt.excludes, actual, tt.expected)
+		}
+	}
+}
+
+func TestCacheExcludesByBuilder(t *testing.T) {
+	cfg := &config.Config{NPM: config.NPMConfig{BuildDir: "out"}}
+	options := &BuildOptions{ProjectPath: "/project", OutputPath: "/project/artifacts"}
+
+	tests := []struct {
+		projectType detector.ProjectType
+		expected    []string
+	}{
+		{projectType: detector.ProjectTypeNPM, expected: []string{"out", "artifacts"}},
+		{projectType: detector.ProjectTypeMaven, expected: []string{"target", "artifacts"}},
+		{projectType: detector.ProjectTypeGradle, expected: []string{"build", ".gradle", "artifacts"}},
+		{projectType: detector.ProjectTypeGo, expected: []string{"artifacts"}},
+		{projectType: detector.ProjectTypePython, expected: []string{"dist", "artifacts"}},
+		{projectType: detector.ProjectTypeDocker, expected: []string{"target", "build", ".gradle", "out", "artifacts"}},
+	}
+
+	for _, tt := range tests {
+		actual := cacheExcludes(cfg, options, tt.projectType)
+		if len(actual) != len(tt.expected) {
+			t.Errorf("%s: 排除目录为 %q，应为 %q", tt.projectType, actual, tt.expected)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != tt.expected[i] {
+				t.Errorf("%s: 排除目录为 %q，应为 %q", tt.projectType, actual, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestComputeCacheKey(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":        "package main",
+		"dist/embed.go":  "package dist",
+		"target/a.class": "class",
+		"build/app.tar":  "artifact",
+	}
+	for name, content := range files {
+		writeTestFile(t, filepath.Join(root, filepath.FromSlash(name)), content)
+	}
+
+	cfg := &config.Config{}
+	options := &BuildOptions{ProjectPath: root}
+	goBuilder := NewGoBuilder(cfg, options)
+	npmBuilder := NewNPMBuilder(cfg, options)
+
+	key := func(builder Builder) string {
+		t.Helper()
+		cacheKey, err := computeCacheKey(cfg, options, builder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cacheKey
+	}
+	goKey, npmKey := key(goBuilder), key(npmBuilder)
+	if goKey == npmKey {
+		t.Fatal("不同构建器的缓存键应不同")
+	}
+
+	// 输出目录 build 中的文件不影响缓存键
+	writeTestFile(t, filepath.Join(root, "build", "app.tar"), "another artifact")
+	if key(goBuilder) != goKey || key(npmBuilder) != npmKey {
+		t.Error("输出目录中的文件不应影响缓存键")
+	}
+
+	// dist 是 NPM 的构建目录，在 Go 项目中是源码
+	writeTestFile(t, filepath.Join(root, "dist", "embed.go"), "package dist // changed")
+	if key(npmBuilder) != npmKey {
+		t.Error("NPM 构建目录中的文件不应影响缓存键")
+	}
+	if newKey := key(goBuilder); newKey == goKey {
+		t.Error("Go 项目中 dist 目录的文件应影响缓存键")
+	} else {
+		goKey = newKey
+	}
+
+	// target 只在 Maven 项目中排除
+	writeTestFile(t, filepath.Join(root, "target", "a.class"), "changed")
+	if key(goBuilder) == goKey {
+		t.Error("Go 项目中 target 目录的文件应影响缓存键")
+	}
+}
+
+// writeTestFile 写入文件，自动创建上级目录
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,28 @@
+package builder
+
+import (
+	"deploy/internal/cache"
+	"deploy/internal/config"
+	"fmt"
+	"os"
+)
+
+// prepareDependencyCache 准备依赖缓存目录，未启用依赖缓存时返回空字符串
+func prepareDependencyCache(cfg *config.Config, kind cache.Kind) (string, error) {
+	dir, err := cache.Prepare(cfg.Cache, cfg.Project.Name, kind)
+	if err != nil {
+		return "", fmt.Errorf("准备依赖缓存失败: %w", err)
+	}
+	if dir != "" {
+		fmt.Printf("📂 依赖缓存: %s\n", dir)
+	}
+	return dir, nil
+}
+
+// cacheEnv 生成设置缓存目录的环境变量，dir 为空时返回 nil 以继承当前环境
+func cacheEnv(name, dir string) []string {
+	if dir == "" {
+		return nil
+	}
+	return append(os.Environ(), name+"="+dir)
+}
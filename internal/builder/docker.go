@@ -0,0 +1,427 @@
+package builder
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/detector"
+	"deploy/internal/utils"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dockerCLIs 支持的容器构建工具，按自动检测的优先级排列
+var dockerCLIs = []string{"docker", "podman", "buildah"}
+
+// DockerBuilder Docker 镜像构建器
+//
+// 项目中存在 Dockerfile 时直接使用，否则先使用对应的构建器构建项目，
+// 再根据项目类型生成 Dockerfile。
+type DockerBuilder struct {
+	config    *config.Config
+	options   *BuildOptions
+	toolchain map[string]string
+	cli       string
+	innerType detector.ProjectType // 生成 Dockerfile 时使用的项目类型
+	inner     Builder
+}
+
+// imageDescriptor 未导出镜像时作为构建产物的镜像描述文件
+type imageDescriptor struct {
+	Image    string   `json:"image"`
+	Tags     []string `json:"tags"`
+	ID       string   `json:"id"`
+	Digest   string   `json:"digest,omitempty"`
+	CLI      string   `json:"cli"`
+	Platform string   `json:"platform,omitempty"`
+}
+
+// NewDockerBuilder 创建 Docker 构建器
+func NewDockerBuilder(config *config.Config, options *BuildOptions) *DockerBuilder {
+	return &DockerBuilder{
+		config:    config,
+		options:   options,
+		toolchain: make(map[string]string),
+	}
+}
+
+// GetType 获取构建器类型
+func (d *DockerBuilder) GetType() detector.ProjectType {
+	return detector.ProjectTypeDocker
+}
+
+// Toolchain 获取工具链版本，在 Validate 之后可用
+func (d *DockerBuilder) Toolchain() map[string]string {
+	return d.toolchain
+}
+
+// Validate 验证构建环境
+func (d *DockerBuilder) Validate() error {
+	if err := d.checkCLI(); err != nil {
+		return fmt.Errorf("容器构建工具检查失败: %w", err)
+	}
+
+	if utils.FileExists(d.dockerfilePath()) {
+		fmt.Printf("✓ 使用 Dockerfile: %s\n", d.dockerfilePath())
+		return nil
+	}
+
+	// 没有 Dockerfile 时需要先构建项目
+	projectInfo, err := detector.DetectProject(d.options.ProjectPath)
+	if err != nil || projectInfo.Type == detector.ProjectTypeDocker {
+		return fmt.Errorf("Dockerfile 不存在，且无法识别用于生成 Dockerfile 的项目类型")
+	}
+
+	inner, err := NewBuilder(projectInfo.Type, d.config, d.options)
+	if err != nil {
+		return err
+	}
+	if err := inner.Validate(); err != nil {
+		return err
+	}
+	for name, version := range inner.Toolchain() {
+		d.toolchain[name] = version
+	}
+
+	d.innerType = projectInfo.Type
+	d.inner = inner
+	return nil
+}
+
+// Build 执行构建
+func (d *DockerBuilder) Build() (*BuildResult, error) {
+	startTime := time.Now()
+
+	fmt.Println("🚀 开始构建 Docker 镜像...")
+
+	// 生成版本号
+	if d.options.Version == "" {
+		d.options.Version = utils.GenerateVersion()
+	}
+
+	dockerfile := d.dockerfilePath()
+	contextDir := d.contextPath()
+
+	// 没有 Dockerfile 时先构建项目，再生成构建上下文
+	if d.inner != nil {
+		innerResult, err := d.inner.Build()
+		if err != nil {
+			return innerResult, err
+		}
+
+		tempDir, err := os.MkdirTemp("", "deploy-docker-*")
+		if err != nil {
+			return nil, fmt.Errorf("创建构建上下文失败: %w", err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		if err := generateDockerContext(d.config, d.options, d.innerType, innerResult, tempDir); err != nil {
+			return &BuildResult{
+				Success: false,
+				Message: fmt.Sprintf("生成 Dockerfile 失败: %v", err),
+			}, err
+		}
+		dockerfile = filepath.Join(tempDir, "Dockerfile")
+		contextDir = tempDir
+	}
+
+	// 切换到项目目录
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(d.options.ProjectPath); err != nil {
+		return nil, fmt.Errorf("切换到项目目录失败: %w", err)
+	}
+
+	outputDir, err := outputDirectory(d.options)
+	if err != nil {
+		return nil, err
+	}
+
+	// 构建镜像
+	refs := d.imageRefs()
+	imageID, err := d.buildImage(dockerfile, contextDir, refs, outputDir)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("镜像构建失败: %v", err),
+		}, err
+	}
+
+	// 推送镜像
+	var digest string
+	if d.config.Docker.Push {
+		digest, err = d.pushImage(refs, outputDir)
+		if err != nil {
+			return &BuildResult{
+				Success: false,
+				Message: fmt.Sprintf("镜像推送失败: %v", err),
+			}, err
+		}
+	}
+
+	// 导出镜像或写入镜像描述文件
+	artifactPath, err := d.writeArtifact(outputDir, refs, imageID, digest)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("生成构建产物失败: %v", err),
+		}, err
+	}
+
+	stat, err := os.Stat(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	result := &BuildResult{
+		Success:      true,
+		ArtifactPath: artifactPath,
+		Version:      d.options.Version,
+		Files:        []string{filepath.Base(artifactPath)},
+		Size:         stat.Size(),
+		Message:      "构建成功",
+		Image:        refs[0],
+		ImageID:      imageID,
+		ImageDigest:  digest,
+	}
+
+	// 生成构建清单
+	if err := writeManifest(manifestInput{
+		ProjectName:  d.config.Project.Name,
+		ProjectPath:  d.options.ProjectPath,
+		Version:      d.options.Version,
+		Builder:      string(d.GetType()),
+		Toolchain:    d.toolchain,
+		BuildCommand: d.describeCommand(refs),
+		ArtifactPath: artifactPath,
+	}, result); err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("生成构建清单失败: %v", err),
+		}, err
+	}
+
+	buildTime := time.Since(startTime)
+	result.BuildTime = buildTime.String()
+
+	fmt.Printf("✅ Docker 镜像构建完成，耗时: %v\n", buildTime)
+	fmt.Printf("🐳 镜像: %s (%s)\n", refs[0], imageID)
+
+	return result, nil
+}
+
+// checkCLI 检查容器构建工具
+func (d *DockerBuilder) checkCLI() error {
+	candidates := dockerCLIs
+	if d.config.Docker.CLI != "" {
+		candidates = []string{d.config.Docker.CLI}
+	}
+
+	for _, cli := range candidates {
+		if _, err := exec.LookPath(cli); err != nil {
+			continue
+		}
+
+		output, err := exec.Command(cli, "--version").Output()
+		if err != nil {
+			return fmt.Errorf("%s 无法执行: %w", cli, err)
+		}
+
+		version := strings.TrimSpace(string(output))
+		d.cli = cli
+		d.toolchain[cli] = version
+		fmt.Printf("✓ %s\n", version)
+		return nil
+	}
+
+	return fmt.Errorf("未找到 %s", strings.Join(candidates, "/"))
+}
+
+// dockerfilePath 获取 Dockerfile 路径
+func (d *DockerBuilder) dockerfilePath() string {
+	dockerfile := d.config.Docker.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	return resolveProjectFile(d.options.ProjectPath, dockerfile)
+}
+
+// contextPath 获取使用已有 Dockerfile 时的构建上下文
+func (d *DockerBuilder) contextPath() string {
+	if d.config.Docker.Context == "" {
+		return d.options.ProjectPath
+	}
+	return resolveProjectFile(d.options.ProjectPath, d.config.Docker.Context)
+}
+
+// imageRefs 获取镜像引用，第一个为版本号标签
+func (d *DockerBuilder) imageRefs() []string {
+	image := d.config.Docker.Image
+	if image == "" {
+		image = strings.ToLower(utils.SanitizeFileName(d.config.Project.Name))
+	}
+
+	refs := []string{image + ":" + d.options.Version}
+	for _, tag := range d.config.Docker.Tags {
+		if tag != d.options.Version {
+			refs = append(refs, image+":"+tag)
+		}
+	}
+	return refs
+}
+
+// buildArgs 获取镜像构建参数
+func (d *DockerBuilder) buildArgs(dockerfile, contextDir string, refs []string, iidFile string) []string {
+	args := []string{"build"}
+	if d.cli == "buildah" {
+		args = []string{"bud"}
+	}
+
+	args = append(args, "-f", dockerfile)
+	for _, ref := range refs {
+		args = append(args, "-t", ref)
+	}
+	if d.config.Docker.Platform != "" {
+		args = append(args, "--platform", d.config.Docker.Platform)
+	}
+
+	names := make([]string, 0, len(d.config.Docker.BuildArgs))
+	for name := range d.config.Docker.BuildArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--build-arg", name+"="+d.config.Docker.BuildArgs[name])
+	}
+
+	if iidFile != "" {
+		args = append(args, "--iidfile", iidFile)
+	}
+	return append(args, contextDir)
+}
+
+// describeCommand 描述构建命令
+func (d *DockerBuilder) describeCommand(refs []string) string {
+	return d.cli + " " + strings.Join(d.buildArgs(d.dockerfilePath(), d.contextPath(), refs, ""), " ")
+}
+
+// buildImage 构建镜像并返回镜像 ID
+func (d *DockerBuilder) buildImage(dockerfile, contextDir string, refs []string, outputDir string) (string, error) {
+	fmt.Printf("🔨 使用 %s 构建镜像 %s...\n", d.cli, refs[0])
+
+	iidFile := filepath.Join(outputDir, ".iid")
+	defer os.Remove(iidFile)
+
+	if err := d.run(d.buildArgs(dockerfile, contextDir, refs, iidFile)...); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(iidFile)
+	if err != nil {
+		return "", fmt.Errorf("读取镜像 ID 失败: %w", err)
+	}
+
+	fmt.Println("✓ 镜像构建完成")
+	return strings.TrimSpace(string(data)), nil
+}
+
+// pushImage 推送所有标签并返回镜像摘要
+func (d *DockerBuilder) pushImage(refs []string, outputDir string) (string, error) {
+	digestFile := filepath.Join(outputDir, ".digest")
+	defer os.Remove(digestFile)
+
+	for _, ref := range refs {
+		fmt.Printf("📤 推送镜像 %s...\n", ref)
+
+		// docker push 不支持 --digestfile，推送后从 RepoDigests 获取摘要
+		args := []string{"push", ref}
+		if d.cli != "docker" {
+			args = []string{"push", "--digestfile", digestFile, ref}
+		}
+		if err := d.run(args...); err != nil {
+			return "", err
+		}
+	}
+
+	if d.cli != "docker" {
+		data, err := os.ReadFile(digestFile)
+		if err != nil {
+			return "", fmt.Errorf("读取镜像摘要失败: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	output, err := exec.Command(d.cli, "image", "inspect", "--format", "{{range .RepoDigests}}{{println .}}{{end}}", refs[0]).Output()
+	if err != nil {
+		return "", fmt.Errorf("获取镜像摘要失败: %w", err)
+	}
+
+	repository := refs[0][:strings.LastIndex(refs[0], ":")]
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, repository+"@") {
+			return strings.TrimPrefix(line, repository+"@"), nil
+		}
+	}
+	return "", fmt.Errorf("未找到 %s 的镜像摘要", repository)
+}
+
+// writeArtifact 导出镜像 (docker.save) 或写入镜像描述文件
+func (d *DockerBuilder) writeArtifact(outputDir string, refs []string, imageID, digest string) (string, error) {
+	baseName := fmt.Sprintf("%s-%s", d.config.Project.Name, d.options.Version)
+
+	if d.config.Docker.Save {
+		artifactPath := filepath.Join(outputDir, baseName+".image.tar")
+		fmt.Printf("📦 导出镜像到 %s...\n", artifactPath)
+
+		os.Remove(artifactPath)
+		args := []string{"save", "-o", artifactPath, refs[0]}
+		if d.cli == "buildah" {
+			args = []string{"push", refs[0], "docker-archive:" + artifactPath + ":" + refs[0]}
+		}
+		if err := d.run(args...); err != nil {
+			return "", err
+		}
+		return artifactPath, nil
+	}
+
+	descriptor := imageDescriptor{
+		Image:    refs[0],
+		Tags:     refs,
+		ID:       imageID,
+		Digest:   digest,
+		CLI:      d.cli,
+		Platform: d.config.Docker.Platform,
+	}
+	data, err := json.MarshalIndent(descriptor, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化镜像描述失败: %w", err)
+	}
+
+	artifactPath := filepath.Join(outputDir, baseName+".image.json")
+	if err := os.WriteFile(artifactPath, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("写入镜像描述失败: %w", err)
+	}
+	return artifactPath, nil
+}
+
+// run 执行容器构建工具命令
+func (d *DockerBuilder) run(args ...string) error {
+	cmd := exec.Command(d.cli, args...)
+	if d.options.Verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("执行 %s %s 失败: %w", d.cli, args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package builder
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/detector"
+	"deploy/internal/utils"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// npmDockerfileTemplate NPM 项目的 Dockerfile，使用 serve 提供构建目录中的静态文件
+const npmDockerfileTemplate = `FROM {{.BaseImage}}
+LABEL org.opencontainers.image.title="{{.Project}}" org.opencontainers.image.version="{{.Version}}"
+WORKDIR /app
+RUN npm install -g serve
+COPY dist/ /app/dist/
+EXPOSE {{.Port}}
+CMD ["serve", "-s", "dist", "-l", "{{.Port}}"]
+`
+
+// javaDockerfileTemplate Java 项目的 Dockerfile，使用 JavaRuntime 配置启动 JAR
+const javaDockerfileTemplate = `FROM {{.BaseImage}}
+LABEL org.opencontainers.image.title="{{.Project}}" org.opencontainers.image.version="{{.Version}}"
+WORKDIR /app
+COPY app.jar /app/app.jar
+EXPOSE {{.Port}}
+ENTRYPOINT {{.Entrypoint}}
+`
+
+// dockerfileData Dockerfile 模板变量
+type dockerfileData struct {
+	Project    string
+	Version    string
+	BaseImage  string
+	Port       int
+	Entrypoint string
+}
+
+// generateDockerContext 根据内部构建器的构建结果生成构建上下文和 Dockerfile
+func generateDockerContext(cfg *config.Config, options *BuildOptions, projectType detector.ProjectType, inner *BuildResult, contextDir string) error {
+	data := dockerfileData{
+		Project:   cfg.Project.Name,
+		Version:   options.Version,
+		BaseImage: cfg.Docker.BaseImage,
+		Port:      cfg.Docker.Port,
+	}
+
+	var text string
+	switch projectType {
+	case detector.ProjectTypeNPM:
+		buildDir := cfg.NPM.BuildDir
+		if buildDir == "" {
+			buildDir = "dist"
+		}
+		if err := copyPath(resolveProjectFile(options.ProjectPath, buildDir), filepath.Join(contextDir, "dist")); err != nil {
+			return fmt.Errorf("复制构建目录失败: %w", err)
+		}
+
+		if data.BaseImage == "" {
+			nodeVersion := cfg.NPM.NodeVersion
+			if nodeVersion == "" {
+				nodeVersion = "18"
+			}
+			data.BaseImage = fmt.Sprintf("node:%s-alpine", nodeVersion)
+		}
+		if data.Port == 0 {
+			data.Port = 3000
+		}
+		text = npmDockerfileTemplate
+
+	case detector.ProjectTypeMaven, detector.ProjectTypeGradle:
+		jarPath := resolveProjectFile(options.ProjectPath, inner.ArtifactPath)
+		if filepath.Ext(jarPath) != ".jar" {
+			return fmt.Errorf("生成 Dockerfile 需要 JAR 构建产物，当前为 %s，请使用已有的 Dockerfile", filepath.Base(jarPath))
+		}
+		if err := utils.CopyFile(jarPath, filepath.Join(contextDir, "app.jar")); err != nil {
+			return fmt.Errorf("复制 JAR 文件失败: %w", err)
+		}
+
+		if data.BaseImage == "" {
+			javaVersion := cfg.Java.JavaVersion
+			if javaVersion == "" {
+				javaVersion = "17"
+			}
+			data.BaseImage = fmt.Sprintf("eclipse-temurin:%s-jre", javaVersion)
+		}
+		if data.Port == 0 {
+			data.Port = 8080
+		}
+
+		entrypoint, err := javaEntrypoint(&cfg.Java.Runtime)
+		if err != nil {
+			return err
+		}
+		data.Entrypoint = entrypoint
+		text = javaDockerfileTemplate
+
+	default:
+		return fmt.Errorf("无法为 %s 项目生成 Dockerfile", projectType)
+	}
+
+	content, err := config.RenderTemplate("Dockerfile", text, data)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入 Dockerfile 失败: %w", err)
+	}
+
+	fmt.Printf("📝 已根据 %s 项目生成 Dockerfile (基础镜像: %s)\n", projectType, data.BaseImage)
+	return nil
+}
+
+// javaEntrypoint 根据 Java 运行时配置生成 exec 形式的 ENTRYPOINT
+func javaEntrypoint(runtime *config.JavaRuntime) (string, error) {
+	args := []string{"java"}
+	if runtime.HeapSize.Min != "" {
+		args = append(args, "-Xms"+runtime.HeapSize.Min)
+	}
+	if runtime.HeapSize.Max != "" {
+		args = append(args, "-Xmx"+runtime.HeapSize.Max)
+	}
+	args = append(args, runtime.JvmOptions...)
+	args = append(args, "-jar", "/app/app.jar")
+	args = append(args, runtime.AppOptions...)
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("生成 ENTRYPOINT 失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// copyPath 复制文件或目录
+func copyPath(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return utils.CopyFile(src, dst)
+	}
+
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return utils.CopyFile(path, target)
+	})
+}
@@ -0,0 +1,352 @@
+package builder
+
+import (
+	"deploy/internal/cache"
+	"deploy/internal/config"
+	"deploy/internal/detector"
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoBuilder Go 构建器
+type GoBuilder struct {
+	config        *config.Config
+	options       *BuildOptions
+	toolchain     map[string]string
+	fileChecksums []manifest.FileChecksum
+	cacheDir      string // 依赖缓存目录，为空时使用 go 的默认目录
+	module        *detector.GoModuleInfo
+}
+
+// goPlatform 目标平台
+type goPlatform struct {
+	OS   string
+	Arch string
+}
+
+// NewGoBuilder 创建 Go 构建器
+func NewGoBuilder(config *config.Config, options *BuildOptions) *GoBuilder {
+	return &GoBuilder{
+		config:    config,
+		options:   options,
+		toolchain: make(map[string]string),
+	}
+}
+
+// GetType 获取构建器类型
+func (g *GoBuilder) GetType() detector.ProjectType {
+	return detector.ProjectTypeGo
+}
+
+// Toolchain 获取工具链版本，在 Validate 之后可用
+func (g *GoBuilder) Toolchain() map[string]string {
+	return g.toolchain
+}
+
+// Validate 验证构建环境
+func (g *GoBuilder) Validate() error {
+	module, err := detector.ParseGoModule(g.options.ProjectPath)
+	if err != nil {
+		return err
+	}
+	g.module = module
+
+	if err := g.checkGo(); err != nil {
+		return fmt.Errorf("Go 环境检查失败: %w", err)
+	}
+
+	if len(g.packages()) == 0 {
+		return fmt.Errorf("未找到 main 包，请在 go.packages 中指定要构建的包")
+	}
+
+	if _, err := g.platforms(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Build 执行构建
+func (g *GoBuilder) Build() (*BuildResult, error) {
+	startTime := time.Now()
+
+	fmt.Println("🚀 开始构建 Go 项目...")
+
+	// 生成版本号
+	if g.options.Version == "" {
+		g.options.Version = utils.GenerateVersion()
+	}
+
+	// 准备依赖缓存目录
+	cacheDir, err := prepareDependencyCache(g.config, cache.KindGo)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	g.cacheDir = cacheDir
+
+	// 切换到项目目录
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(g.options.ProjectPath); err != nil {
+		return nil, fmt.Errorf("切换到项目目录失败: %w", err)
+	}
+
+	stagingDir, err := os.MkdirTemp("", "deploy-go-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	// 编译所有平台的二进制文件
+	if err := g.runGoBuild(stagingDir); err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("Go 构建失败: %v", err),
+		}, err
+	}
+
+	// 打包构建产物
+	artifactPath, files, size, err := g.packageArtifacts(stagingDir)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("打包失败: %v", err),
+		}, err
+	}
+
+	result := &BuildResult{
+		Success:      true,
+		ArtifactPath: artifactPath,
+		Version:      g.options.Version,
+		Files:        files,
+		Size:         size,
+		Message:      "构建成功",
+	}
+
+	// 生成构建清单
+	if err := writeManifest(manifestInput{
+		ProjectName:  g.config.Project.Name,
+		ProjectPath:  g.options.ProjectPath,
+		Version:      g.options.Version,
+		Builder:      string(g.GetType()),
+		Toolchain:    g.toolchain,
+		BuildCommand: g.buildCommand(),
+		ArtifactPath: artifactPath,
+		Files:        g.fileChecksums,
+	}, result); err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("生成构建清单失败: %v", err),
+		}, err
+	}
+
+	buildTime := time.Since(startTime)
+	result.BuildTime = buildTime.String()
+
+	fmt.Printf("✅ Go 项目构建完成，耗时: %v\n", buildTime)
+	fmt.Printf("📦 构建产物: %s (%.2f MB)\n", artifactPath, float64(size)/(1024*1024))
+
+	return result, nil
+}
+
+// checkGo 检查 Go 环境，版本低于要求时给出警告
+func (g *GoBuilder) checkGo() error {
+	output, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		return fmt.Errorf("Go 未安装或不在 PATH 中")
+	}
+
+	version := strings.TrimSpace(string(output))
+	g.toolchain["go"] = version
+	fmt.Printf("✓ Go 版本: %s\n", version)
+
+	required := g.config.Go.GoVersion
+	if required == "" {
+		required = g.module.GoVersion
+	}
+	if required != "" {
+		fmt.Printf("  要求版本: %s\n", required)
+		if compareVersions(strings.TrimPrefix(version, "go"), strings.TrimPrefix(required, "go")) < 0 {
+			utils.PrintWarning(fmt.Sprintf("当前 Go 版本 %s 低于要求的 %s", version, required))
+		}
+	}
+
+	return nil
+}
+
+// packages 获取要构建的 main 包
+func (g *GoBuilder) packages() []string {
+	if len(g.config.Go.Packages) > 0 {
+		return g.config.Go.Packages
+	}
+	if g.module == nil {
+		return nil
+	}
+	return g.module.MainPackages
+}
+
+// platforms 解析目标平台，默认为当前平台
+func (g *GoBuilder) platforms() ([]goPlatform, error) {
+	if len(g.config.Go.Platforms) == 0 {
+		return []goPlatform{{OS: runtime.GOOS, Arch: runtime.GOARCH}}, nil
+	}
+
+	var platforms []goPlatform
+	for _, platform := range g.config.Go.Platforms {
+		parts := strings.Split(platform, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("目标平台格式无效: %s (应为 GOOS/GOARCH)", platform)
+		}
+		platforms = append(platforms, goPlatform{OS: parts[0], Arch: parts[1]})
+	}
+	return platforms, nil
+}
+
+// ldflags 获取链接参数，通过 -X 写入版本号
+func (g *GoBuilder) ldflags() string {
+	variable := g.config.Go.VersionVariable
+	if variable == "" {
+		variable = "main.version"
+	}
+
+	flags := fmt.Sprintf("-X %s=%s", variable, g.options.Version)
+	if g.config.Go.LDFlags != "" {
+		flags = g.config.Go.LDFlags + " " + flags
+	}
+	return flags
+}
+
+// buildArgs 获取 go build 参数
+func (g *GoBuilder) buildArgs(output, pkg string) []string {
+	args := []string{"build", "-trimpath", "-ldflags", g.ldflags()}
+	if len(g.config.Go.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(g.config.Go.Tags, ","))
+	}
+	return append(args, "-o", output, pkg)
+}
+
+// buildCommand 描述构建命令
+func (g *GoBuilder) buildCommand() string {
+	platforms, _ := g.platforms()
+	targets := make([]string, 0, len(platforms))
+	for _, platform := range platforms {
+		targets = append(targets, platform.OS+"/"+platform.Arch)
+	}
+
+	args := g.buildArgs("<output>", strings.Join(g.packages(), " "))
+	args[3] = strconv.Quote(args[3])
+	return fmt.Sprintf("go %s (%s)", strings.Join(args, " "), strings.Join(targets, ", "))
+}
+
+// binaryName 获取 main 包对应的二进制文件名
+func (g *GoBuilder) binaryName(pkg string, platform goPlatform) string {
+	name := path.Base(filepath.ToSlash(filepath.Clean(pkg)))
+	if name == "." || name == "/" {
+		name = path.Base(g.module.Module)
+	}
+	if platform.OS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// binaryDir 获取平台的二进制文件目录，只有一个平台时直接放在 bin/
+func (g *GoBuilder) binaryDir(stagingDir string, platform goPlatform, multiple bool) string {
+	if !multiple {
+		return filepath.Join(stagingDir, "bin")
+	}
+	return filepath.Join(stagingDir, "bin", platform.OS+"_"+platform.Arch)
+}
+
+// runGoBuild 为每个平台编译所有 main 包
+func (g *GoBuilder) runGoBuild(stagingDir string) error {
+	platforms, err := g.platforms()
+	if err != nil {
+		return err
+	}
+
+	cgo := "0"
+	if g.config.Go.CGO {
+		cgo = "1"
+	}
+
+	for _, platform := range platforms {
+		fmt.Printf("🔨 编译 %s/%s...\n", platform.OS, platform.Arch)
+
+		for _, pkg := range g.packages() {
+			output := filepath.Join(g.binaryDir(stagingDir, platform, len(platforms) > 1), g.binaryName(pkg, platform))
+
+			cmd := exec.Command("go", g.buildArgs(output, pkg)...)
+			cmd.Env = append(os.Environ(), "GOOS="+platform.OS, "GOARCH="+platform.Arch, "CGO_ENABLED="+cgo)
+			if g.cacheDir != "" {
+				cmd.Env = append(cmd.Env, "GOMODCACHE="+g.cacheDir)
+			}
+
+			if g.options.Verbose {
+				cmd.Stdout = os.Stdout
+				cmd.Stderr = os.Stderr
+			}
+
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("编译 %s (%s/%s) 失败: %w", pkg, platform.OS, platform.Arch, err)
+			}
+			fmt.Printf("  ✓ %s\n", g.binaryName(pkg, platform))
+		}
+	}
+
+	fmt.Println("✓ Go 构建完成")
+	return nil
+}
+
+// packageArtifacts 打包构建产物
+func (g *GoBuilder) packageArtifacts(stagingDir string) (string, []string, int64, error) {
+	fmt.Println("📦 打包构建产物...")
+
+	outputDir, err := outputDirectory(g.options)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	opts, err := resolveArchiveOptions(g.config, g.options, FormatTarGz, g.options.Reproducible)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	entries, err := collectDirEntries(stagingDir, "")
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("遍历构建目录失败: %w", err)
+	}
+
+	entries, err = applyArtifactSelection(g.config, g.options.ProjectPath, entries)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	artifactName := fmt.Sprintf("%s-%s%s", g.config.Project.Name, g.options.Version, artifactExtension(opts.Format))
+	artifactPath := filepath.Join(outputDir, artifactName)
+
+	files, checksums, size, err := writeArtifact(artifactPath, entries, opts)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("打包文件失败: %w", err)
+	}
+	g.fileChecksums = checksums
+
+	fmt.Printf("✓ 打包完成: %s\n", artifactPath)
+	return artifactPath, files, size, nil
+}
@@ -1,8 +1,11 @@
 package builder
 
 import (
+	"deploy/internal/cache"
 	"deploy/internal/config"
 	"deploy/internal/detector"
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,15 +16,19 @@ import (
 
 // GradleBuilder Gradle 构建器
 type GradleBuilder struct {
-	config  *config.Config
-	options *BuildOptions
+	config        *config.Config
+	options       *BuildOptions
+	toolchain     map[string]string
+	fileChecksums []manifest.FileChecksum
+	cacheDir      string // 依赖缓存目录，为空时使用构建工具的默认目录
 }
 
 // NewGradleBuilder 创建 Gradle 构建器
 func NewGradleBuilder(config *config.Config, options *BuildOptions) *GradleBuilder {
 	return &GradleBuilder{
-		config:  config,
-		options: options,
+		config:    config,
+		options:   options,
+		toolchain: make(map[string]string),
 	}
 }
 
@@ -30,6 +37,11 @@ func (g *GradleBuilder) GetType() detector.ProjectType {
 	return detector.ProjectTypeGradle
 }
 
+// Toolchain 获取工具链版本，在 Validate 之后可用
+func (g *GradleBuilder) Toolchain() map[string]string {
+	return g.toolchain
+}
+
 // Validate 验证构建环境
 func (g *GradleBuilder) Validate() error {
 	// 检查 Java 是否安装
@@ -61,6 +73,21 @@ func (g *GradleBuilder) Build() (*BuildResult, error) {
 
 	fmt.Println("🚀 开始构建 Gradle 项目...")
 
+	// 生成版本号
+	if g.options.Version == "" {
+		g.options.Version = utils.GenerateVersion()
+	}
+
+	// 准备依赖缓存目录
+	cacheDir, err := prepareDependencyCache(g.config, cache.KindGradle)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	g.cacheDir = cacheDir
+
 	// 切换到项目目录
 	originalDir, err := os.Getwd()
 	if err != nil {
@@ -89,20 +116,39 @@ func (g *GradleBuilder) Build() (*BuildResult, error) {
 		}, err
 	}
 
-	buildTime := time.Since(startTime)
-
-	fmt.Printf("✅ Gradle 项目构建完成，耗时: %v\n", buildTime)
-	fmt.Printf("📦 构建产物: %s (%.2f MB)\n", artifactPath, float64(size)/(1024*1024))
-
-	return &BuildResult{
+	result := &BuildResult{
 		Success:      true,
 		ArtifactPath: artifactPath,
 		Version:      g.options.Version,
-		BuildTime:    buildTime.String(),
 		Files:        files,
 		Size:         size,
 		Message:      "构建成功",
-	}, nil
+	}
+
+	// 生成构建清单
+	if err := writeManifest(manifestInput{
+		ProjectName:  g.config.Project.Name,
+		ProjectPath:  g.options.ProjectPath,
+		Version:      g.options.Version,
+		Builder:      string(g.GetType()),
+		Toolchain:    g.toolchain,
+		BuildCommand: g.buildCommand(),
+		ArtifactPath: artifactPath,
+		Files:        g.fileChecksums,
+	}, result); err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("生成构建清单失败: %v", err),
+		}, err
+	}
+
+	buildTime := time.Since(startTime)
+	result.BuildTime = buildTime.String()
+
+	fmt.Printf("✅ Gradle 项目构建完成，耗时: %v\n", buildTime)
+	fmt.Printf("📦 构建产物: %s (%.2f MB)\n", artifactPath, float64(size)/(1024*1024))
+
+	return result, nil
 }
 
 // checkJava 检查 Java 环境
@@ -113,8 +159,9 @@ func (g *GradleBuilder) checkJava() error {
 		return fmt.Errorf("Java 未安装或不在 PATH 中")
 	}
 
-	version := strings.Split(string(output), "\n")[0]
-	fmt.Printf("✓ Java 版本: %s\n", strings.TrimSpace(version))
+	version := strings.TrimSpace(strings.Split(string(output), "\n")[0])
+	g.toolchain["java"] = version
+	fmt.Printf("✓ Java 版本: %s\n", version)
 
 	// 检查版本是否符合要求
 	if g.config.Java.JavaVersion != "" {
@@ -141,6 +188,7 @@ func (g *GradleBuilder) checkGradle() error {
 			lines := strings.Split(string(output), "\n")
 			for _, line := range lines {
 				if strings.Contains(line, "Gradle") {
+					g.toolchain["gradle"] = strings.TrimSpace(line)
 					fmt.Printf("✓ %s (使用项目 gradlew)\n", strings.TrimSpace(line))
 					break
 				}
@@ -159,6 +207,7 @@ func (g *GradleBuilder) checkGradle() error {
 	lines := strings.Split(string(output), "\n")
 	for _, line := range lines {
 		if strings.Contains(line, "Gradle") {
+			g.toolchain["gradle"] = strings.TrimSpace(line)
 			fmt.Printf("✓ %s (使用系统 gradle)\n", strings.TrimSpace(line))
 			break
 		}
@@ -173,23 +222,11 @@ func (g *GradleBuilder) runGradleBuild() error {
 
 	// 确定使用的 Gradle 命令
 	gradleCmd := g.getGradleCommand()
-
-	// 构建任务
-	tasks := []string{"clean", "build"}
-	if g.options.SkipTests {
-		tasks = []string{"clean", "build", "-x", "test"}
-	}
-
-	// 从配置中获取自定义构建命令
-	if g.config.Java.BuildCommand != "" && g.config.Java.BuildTool == "gradle" {
-		parts := strings.Fields(g.config.Java.BuildCommand)
-		if len(parts) > 1 {
-			tasks = parts[1:] // 跳过 gradle/gradlew 命令本身
-		}
-	}
+	tasks := g.getGradleTasks()
 
 	cmd := exec.Command(gradleCmd, tasks...)
 	cmd.Dir = g.options.ProjectPath
+	cmd.Env = cacheEnv("GRADLE_USER_HOME", g.cacheDir)
 
 	if g.options.Verbose {
 		cmd.Stdout = os.Stdout
@@ -204,6 +241,27 @@ func (g *GradleBuilder) runGradleBuild() error {
 	return nil
 }
 
+// getGradleTasks 获取 Gradle 构建任务
+func (g *GradleBuilder) getGradleTasks() []string {
+	// 从配置中获取自定义构建命令
+	if g.config.Java.BuildCommand != "" && g.config.Java.BuildTool == "gradle" {
+		parts := strings.Fields(g.config.Java.BuildCommand)
+		if len(parts) > 1 {
+			return parts[1:] // 跳过 gradle/gradlew 命令本身
+		}
+	}
+
+	if g.options.SkipTests {
+		return []string{"clean", "build", "-x", "test"}
+	}
+	return []string{"clean", "build"}
+}
+
+// buildCommand 获取完整的 Gradle 构建命令
+func (g *GradleBuilder) buildCommand() string {
+	return fmt.Sprintf("%s %s", g.getGradleCommand(), strings.Join(g.getGradleTasks(), " "))
+}
+
 // getGradleCommand 获取 Gradle 命令
 func (g *GradleBuilder) getGradleCommand() string {
 	// 优先使用项目本地的 gradlew
@@ -234,12 +292,21 @@ func (g *GradleBuilder) packageArtifacts() (string, []string, int64, error) {
 	mainJar := g.selectMainJar(jarFiles)
 
 	// 创建输出目录
-	outputDir := g.options.OutputPath
-	if outputDir == "" {
-		outputDir = "./build"
+	outputDir, err := outputDirectory(g.options)
+	if err != nil {
+		return "", nil, 0, err
 	}
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return "", nil, 0, fmt.Errorf("创建输出目录失败: %w", err)
+
+	// 按配置打包为压缩包或部署包
+	if usesJavaArchive(g.config) {
+		artifactPath, files, checksums, size, err := packageJavaArchive(g.config, g.options, mainJar, outputDir)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		g.fileChecksums = checksums
+
+		fmt.Printf("✓ 打包完成: %s\n", artifactPath)
+		return artifactPath, files, size, nil
 	}
 
 	// 生成版本号
@@ -249,7 +316,7 @@ func (g *GradleBuilder) packageArtifacts() (string, []string, int64, error) {
 	}
 
 	// 复制 JAR 文件到输出目录
-	artifactName := fmt.Sprintf("%s-%s.jar", g.config.Project.Name, version)
+	artifactName := fmt.Sprintf("%s-%s%s", g.config.Project.Name, version, filepath.Ext(mainJar))
 	artifactPath := filepath.Join(outputDir, artifactName)
 
 	if err := g.copyFile(mainJar, artifactPath); err != nil {
@@ -271,6 +338,11 @@ func (g *GradleBuilder) packageArtifacts() (string, []string, int64, error) {
 
 // findJarFiles 查找 JAR 文件
 func (g *GradleBuilder) findJarFiles() ([]string, error) {
+	// 优先使用 java.artifact_path 指定的构建产物
+	if files, err := configuredJavaArtifacts(g.config); err != nil || len(files) > 0 {
+		return files, err
+	}
+
 	buildDir := "build/libs"
 
 	var jarFiles []string
@@ -0,0 +1,94 @@
+package builder
+
+import (
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifestInput 生成构建清单所需的信息
+type manifestInput struct {
+	ProjectName  string
+	ProjectPath  string
+	Version      string
+	Builder      string
+	Toolchain    map[string]string
+	BuildCommand string
+	ArtifactPath string
+	Files        []manifest.FileChecksum
+}
+
+// writeManifest 生成构建清单和 .sha256 校验文件，并将结果写入 BuildResult
+func writeManifest(input manifestInput, result *BuildResult) error {
+	fmt.Println("📝 生成构建清单...")
+
+	info, err := os.Stat(input.ArtifactPath)
+	if err != nil {
+		return fmt.Errorf("获取构建产物信息失败: %w", err)
+	}
+
+	// 目录产物的校验和由目录内所有文件的校验和计算得出
+	var checksum string
+	var size int64
+	if info.IsDir() {
+		checksum = manifest.DirChecksum(input.Files)
+		for _, f := range input.Files {
+			size += f.Size
+		}
+	} else {
+		checksum, size, err = manifest.FileSHA256(input.ArtifactPath)
+		if err != nil {
+			return fmt.Errorf("计算构建产物校验和失败: %w", err)
+		}
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	m := &manifest.Manifest{
+		Project:      input.ProjectName,
+		Version:      input.Version,
+		Builder:      input.Builder,
+		Toolchain:    input.Toolchain,
+		BuildCommand: input.BuildCommand,
+		Timestamp:    time.Now().Format(time.RFC3339),
+		Host:         host,
+		Artifact: manifest.FileChecksum{
+			Path:   filepath.Base(input.ArtifactPath),
+			Size:   size,
+			SHA256: checksum,
+		},
+		Files: input.Files,
+	}
+
+	// Git 信息是可选的，非 Git 项目也允许构建
+	if gitInfo, err := utils.GetGitInfo(input.ProjectPath); err == nil {
+		m.Git = gitInfo
+	}
+
+	manifestPath := manifest.ManifestPath(input.ArtifactPath)
+	if err := manifest.Save(m, manifestPath); err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		err = manifest.WriteDirChecksumFile(input.ArtifactPath, input.Files)
+	} else {
+		err = manifest.WriteChecksumFile(input.ArtifactPath, checksum)
+	}
+	if err != nil {
+		return err
+	}
+
+	result.Checksum = checksum
+	result.ManifestPath = manifestPath
+	result.ChecksumPath = manifest.ChecksumPath(input.ArtifactPath)
+
+	fmt.Printf("✓ SHA-256: %s\n", checksum)
+	return nil
+}
@@ -1,8 +1,11 @@
 package builder
 
 import (
+	"deploy/internal/cache"
 	"deploy/internal/config"
 	"deploy/internal/detector"
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,15 +16,19 @@ import (
 
 // MavenBuilder Maven 构建器
 type MavenBuilder struct {
-	config  *config.Config
-	options *BuildOptions
+	config        *config.Config
+	options       *BuildOptions
+	toolchain     map[string]string
+	fileChecksums []manifest.FileChecksum
+	cacheDir      string // 依赖缓存目录，为空时使用构建工具的默认目录
 }
 
 // NewMavenBuilder 创建 Maven 构建器
 func NewMavenBuilder(config *config.Config, options *BuildOptions) *MavenBuilder {
 	return &MavenBuilder{
-		config:  config,
-		options: options,
+		config:    config,
+		options:   options,
+		toolchain: make(map[string]string),
 	}
 }
 
@@ -30,6 +37,11 @@ func (m *MavenBuilder) GetType() detector.ProjectType {
 	return detector.ProjectTypeMaven
 }
 
+// Toolchain 获取工具链版本，在 Validate 之后可用
+func (m *MavenBuilder) Toolchain() map[string]string {
+	return m.toolchain
+}
+
 // Validate 验证构建环境
 func (m *MavenBuilder) Validate() error {
 	// 检查 Java 是否安装
@@ -57,6 +69,21 @@ func (m *MavenBuilder) Build() (*BuildResult, error) {
 
 	fmt.Println("🚀 开始构建 Maven 项目...")
 
+	// 生成版本号
+	if m.options.Version == "" {
+		m.options.Version = utils.GenerateVersion()
+	}
+
+	// 准备依赖缓存目录
+	cacheDir, err := prepareDependencyCache(m.config, cache.KindMaven)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	m.cacheDir = cacheDir
+
 	// 切换到项目目录
 	originalDir, err := os.Getwd()
 	if err != nil {
@@ -85,20 +112,39 @@ func (m *MavenBuilder) Build() (*BuildResult, error) {
 		}, err
 	}
 
-	buildTime := time.Since(startTime)
-
-	fmt.Printf("✅ Maven 项目构建完成，耗时: %v\n", buildTime)
-	fmt.Printf("📦 构建产物: %s (%.2f MB)\n", artifactPath, float64(size)/(1024*1024))
-
-	return &BuildResult{
+	result := &BuildResult{
 		Success:      true,
 		ArtifactPath: artifactPath,
 		Version:      m.options.Version,
-		BuildTime:    buildTime.String(),
 		Files:        files,
 		Size:         size,
 		Message:      "构建成功",
-	}, nil
+	}
+
+	// 生成构建清单
+	if err := writeManifest(manifestInput{
+		ProjectName:  m.config.Project.Name,
+		ProjectPath:  m.options.ProjectPath,
+		Version:      m.options.Version,
+		Builder:      string(m.GetType()),
+		Toolchain:    m.toolchain,
+		BuildCommand: m.buildCommand(),
+		ArtifactPath: artifactPath,
+		Files:        m.fileChecksums,
+	}, result); err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("生成构建清单失败: %v", err),
+		}, err
+	}
+
+	buildTime := time.Since(startTime)
+	result.BuildTime = buildTime.String()
+
+	fmt.Printf("✅ Maven 项目构建完成，耗时: %v\n", buildTime)
+	fmt.Printf("📦 构建产物: %s (%.2f MB)\n", artifactPath, float64(size)/(1024*1024))
+
+	return result, nil
 }
 
 // checkJava 检查 Java 环境
@@ -109,8 +155,9 @@ func (m *MavenBuilder) checkJava() error {
 		return fmt.Errorf("Java 未安装或不在 PATH 中")
 	}
 
-	version := strings.Split(string(output), "\n")[0]
-	fmt.Printf("✓ Java 版本: %s\n", strings.TrimSpace(version))
+	version := strings.TrimSpace(strings.Split(string(output), "\n")[0])
+	m.toolchain["java"] = version
+	fmt.Printf("✓ Java 版本: %s\n", version)
 
 	// 检查版本是否符合要求
 	if m.config.Java.JavaVersion != "" {
@@ -130,6 +177,7 @@ func (m *MavenBuilder) checkMaven() error {
 
 	lines := strings.Split(string(output), "\n")
 	if len(lines) > 0 {
+		m.toolchain["maven"] = strings.TrimSpace(lines[0])
 		fmt.Printf("✓ Maven 版本: %s\n", strings.TrimSpace(lines[0]))
 	}
 
@@ -140,17 +188,15 @@ func (m *MavenBuilder) checkMaven() error {
 func (m *MavenBuilder) runMavenBuild() error {
 	fmt.Println("🔨 执行 Maven 构建...")
 
-	buildCmd := m.config.Java.BuildCommand
-	if buildCmd == "" {
-		if m.options.SkipTests {
-			buildCmd = "mvn clean package -DskipTests"
-		} else {
-			buildCmd = "mvn clean package"
-		}
-	}
-
+	buildCmd := m.buildCommand()
 	parts := strings.Fields(buildCmd)
-	cmd := exec.Command(parts[0], parts[1:]...)
+
+	// 使用依赖缓存目录作为本地仓库，构建命令中已指定时不覆盖
+	args := parts[1:]
+	if m.cacheDir != "" && !strings.Contains(buildCmd, "maven.repo.local") {
+		args = append([]string{"-Dmaven.repo.local=" + m.cacheDir}, args...)
+	}
+	cmd := exec.Command(parts[0], args...)
 
 	if m.options.Verbose {
 		cmd.Stdout = os.Stdout
@@ -165,6 +211,17 @@ func (m *MavenBuilder) runMavenBuild() error {
 	return nil
 }
 
+// buildCommand 获取 Maven 构建命令
+func (m *MavenBuilder) buildCommand() string {
+	if m.config.Java.BuildCommand != "" {
+		return m.config.Java.BuildCommand
+	}
+	if m.options.SkipTests {
+		return "mvn clean package -DskipTests"
+	}
+	return "mvn clean package"
+}
+
 // packageArtifacts 打包构建产物
 func (m *MavenBuilder) packageArtifacts() (string, []string, int64, error) {
 	fmt.Println("📦 查找并打包构建产物...")
@@ -183,12 +240,21 @@ func (m *MavenBuilder) packageArtifacts() (string, []string, int64, error) {
 	mainJar := m.selectMainJar(jarFiles)
 
 	// 创建输出目录
-	outputDir := m.options.OutputPath
-	if outputDir == "" {
-		outputDir = "./build"
+	outputDir, err := outputDirectory(m.options)
+	if err != nil {
+		return "", nil, 0, err
 	}
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return "", nil, 0, fmt.Errorf("创建输出目录失败: %w", err)
+
+	// 按配置打包为压缩包或部署包
+	if usesJavaArchive(m.config) {
+		artifactPath, files, checksums, size, err := packageJavaArchive(m.config, m.options, mainJar, outputDir)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		m.fileChecksums = checksums
+
+		fmt.Printf("✓ 打包完成: %s\n", artifactPath)
+		return artifactPath, files, size, nil
 	}
 
 	// 生成版本号
@@ -198,7 +264,7 @@ func (m *MavenBuilder) packageArtifacts() (string, []string, int64, error) {
 	}
 
 	// 复制 JAR 文件到输出目录
-	artifactName := fmt.Sprintf("%s-%s.jar", m.config.Project.Name, version)
+	artifactName := fmt.Sprintf("%s-%s%s", m.config.Project.Name, version, filepath.Ext(mainJar))
 	artifactPath := filepath.Join(outputDir, artifactName)
 
 	if err := m.copyFile(mainJar, artifactPath); err != nil {
@@ -220,6 +286,11 @@ func (m *MavenBuilder) packageArtifacts() (string, []string, int64, error) {
 
 // findJarFiles 查找 JAR 文件
 func (m *MavenBuilder) findJarFiles() ([]string, error) {
+	// 优先使用 java.artifact_path 指定的构建产物
+	if files, err := configuredJavaArtifacts(m.config); err != nil || len(files) > 0 {
+		return files, err
+	}
+
 	targetDir := "target"
 
 	var jarFiles []string
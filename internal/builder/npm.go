@@ -1,12 +1,12 @@
 package builder
 
 import (
-	"archive/tar"
-	"compress/gzip"
+	"deploy/internal/cache"
 	"deploy/internal/config"
 	"deploy/internal/detector"
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,15 +16,19 @@ import (
 
 // NPMBuilder NPM 构建器
 type NPMBuilder struct {
-	config  *config.Config
-	options *BuildOptions
+	config        *config.Config
+	options       *BuildOptions
+	toolchain     map[string]string
+	fileChecksums []manifest.FileChecksum
+	cacheDir      string // 依赖缓存目录，为空时使用构建工具的默认目录
 }
 
 // NewNPMBuilder 创建 NPM 构建器
 func NewNPMBuilder(config *config.Config, options *BuildOptions) *NPMBuilder {
 	return &NPMBuilder{
-		config:  config,
-		options: options,
+		config:    config,
+		options:   options,
+		toolchain: make(map[string]string),
 	}
 }
 
@@ -33,6 +37,11 @@ func (n *NPMBuilder) GetType() detector.ProjectType {
 	return detector.ProjectTypeNPM
 }
 
+// Toolchain 获取工具链版本，在 Validate 之后可用
+func (n *NPMBuilder) Toolchain() map[string]string {
+	return n.toolchain
+}
+
 // Validate 验证构建环境
 func (n *NPMBuilder) Validate() error {
 	// 检查 Node.js 是否安装
@@ -60,6 +69,21 @@ func (n *NPMBuilder) Build() (*BuildResult, error) {
 
 	fmt.Println("🚀 开始构建 NPM 项目...")
 
+	// 生成版本号
+	if n.options.Version == "" {
+		n.options.Version = utils.GenerateVersion()
+	}
+
+	// 准备依赖缓存目录
+	cacheDir, err := prepareDependencyCache(n.config, cache.KindNPM)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	n.cacheDir = cacheDir
+
 	// 切换到项目目录
 	originalDir, err := os.Getwd()
 	if err != nil {
@@ -96,20 +120,39 @@ func (n *NPMBuilder) Build() (*BuildResult, error) {
 		}, err
 	}
 
-	buildTime := time.Since(startTime)
-
-	fmt.Printf("✅ NPM 项目构建完成，耗时: %v\n", buildTime)
-	fmt.Printf("📦 构建产物: %s (%.2f MB)\n", artifactPath, float64(size)/(1024*1024))
-
-	return &BuildResult{
+	result := &BuildResult{
 		Success:      true,
 		ArtifactPath: artifactPath,
 		Version:      n.options.Version,
-		BuildTime:    buildTime.String(),
 		Files:        files,
 		Size:         size,
 		Message:      "构建成功",
-	}, nil
+	}
+
+	// 生成构建清单
+	if err := writeManifest(manifestInput{
+		ProjectName:  n.config.Project.Name,
+		ProjectPath:  n.options.ProjectPath,
+		Version:      n.options.Version,
+		Builder:      string(n.GetType()),
+		Toolchain:    n.toolchain,
+		BuildCommand: n.describeCommands(),
+		ArtifactPath: artifactPath,
+		Files:        n.fileChecksums,
+	}, result); err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("生成构建清单失败: %v", err),
+		}, err
+	}
+
+	buildTime := time.Since(startTime)
+	result.BuildTime = buildTime.String()
+
+	fmt.Printf("✅ NPM 项目构建完成，耗时: %v\n", buildTime)
+	fmt.Printf("📦 构建产物: %s (%.2f MB)\n", artifactPath, float64(size)/(1024*1024))
+
+	return result, nil
 }
 
 // checkNodeJS 检查 Node.js 环境
@@ -121,6 +164,7 @@ func (n *NPMBuilder) checkNodeJS() error {
 	}
 
 	version := strings.TrimSpace(string(output))
+	n.toolchain["node"] = version
 	fmt.Printf("✓ Node.js 版本: %s\n", version)
 
 	// 可以在这里检查版本是否符合要求
@@ -141,6 +185,7 @@ func (n *NPMBuilder) checkNPM() error {
 	}
 
 	version := strings.TrimSpace(string(output))
+	n.toolchain["npm"] = version
 	fmt.Printf("✓ npm 版本: %s\n", version)
 
 	return nil
@@ -150,13 +195,10 @@ func (n *NPMBuilder) checkNPM() error {
 func (n *NPMBuilder) installDependencies() error {
 	fmt.Println("📦 安装依赖...")
 
-	installCmd := n.config.NPM.InstallCommand
-	if installCmd == "" {
-		installCmd = "npm ci"
-	}
-
+	installCmd := n.installCommand()
 	parts := strings.Fields(installCmd)
 	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd.Env = cacheEnv("npm_config_cache", n.cacheDir)
 
 	if n.options.Verbose {
 		cmd.Stdout = os.Stdout
@@ -175,13 +217,10 @@ func (n *NPMBuilder) installDependencies() error {
 func (n *NPMBuilder) runBuild() error {
 	fmt.Println("🔨 执行构建...")
 
-	buildCmd := n.config.NPM.BuildCommand
-	if buildCmd == "" {
-		buildCmd = "npm run build"
-	}
-
+	buildCmd := n.buildCommand()
 	parts := strings.Fields(buildCmd)
 	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd.Env = cacheEnv("npm_config_cache", n.cacheDir)
 
 	if n.options.Verbose {
 		cmd.Stdout = os.Stdout
@@ -196,6 +235,36 @@ func (n *NPMBuilder) runBuild() error {
 	return nil
 }
 
+// installCommand 获取依赖安装命令
+func (n *NPMBuilder) installCommand() string {
+	if n.config.NPM.InstallCommand == "" {
+		return "npm ci"
+	}
+	return n.config.NPM.InstallCommand
+}
+
+// describeCommands 描述构建过程中执行的命令
+func (n *NPMBuilder) describeCommands() string {
+	commands := fmt.Sprintf("%s && %s", n.installCommand(), n.buildCommand())
+	if n.config.NPM.ProductionDependencies {
+		commands += " && " + n.productionInstallCommand()
+	}
+	return commands
+}
+
+// buildCommand 获取构建命令
+func (n *NPMBuilder) buildCommand() string {
+	if n.config.NPM.BuildCommand == "" {
+		return "npm run build"
+	}
+	return n.config.NPM.BuildCommand
+}
+
+// reproducible 是否启用可重现打包
+func (n *NPMBuilder) reproducible() bool {
+	return n.options.Reproducible || n.config.NPM.Reproducible
+}
+
 // packageArtifacts 打包构建产物
 func (n *NPMBuilder) packageArtifacts() (string, []string, int64, error) {
 	fmt.Println("📦 打包构建产物...")
@@ -211,96 +280,50 @@ func (n *NPMBuilder) packageArtifacts() (string, []string, int64, error) {
 	}
 
 	// 创建输出目录
-	outputDir := n.options.OutputPath
-	if outputDir == "" {
-		outputDir = "./build"
-	}
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return "", nil, 0, fmt.Errorf("创建输出目录失败: %w", err)
+	outputDir, err := outputDirectory(n.options)
+	if err != nil {
+		return "", nil, 0, err
 	}
 
-	// 生成版本号
-	version := n.options.Version
-	if version == "" {
-		version = time.Now().Format("20060102-150405")
+	opts, err := resolveArchiveOptions(n.config, n.options, FormatTarGz, n.reproducible())
+	if err != nil {
+		return "", nil, 0, err
 	}
 
-	// 创建 tar.gz 文件
-	artifactName := fmt.Sprintf("%s-%s.tar.gz", n.config.Project.Name, version)
-	artifactPath := filepath.Join(outputDir, artifactName)
-
-	file, err := os.Create(artifactPath)
+	// 收集构建目录中的文件
+	entries, err := collectDirEntries(buildDir, "")
 	if err != nil {
-		return "", nil, 0, fmt.Errorf("创建压缩文件失败: %w", err)
+		return "", nil, 0, fmt.Errorf("遍历构建目录失败: %w", err)
 	}
-	defer file.Close()
-
-	gzWriter := gzip.NewWriter(file)
-	defer gzWriter.Close()
-
-	tarWriter := tar.NewWriter(gzWriter)
-	defer tarWriter.Close()
-
-	var files []string
-	var totalSize int64
-
-	// 遍历构建目录并添加到 tar 文件
-	err = filepath.Walk(buildDir, func(path string, info os.FileInfo, err error) error {
+	// 打包生产依赖，目标服务器无需再执行 npm install
+	if n.config.NPM.ProductionDependencies {
+		stagingDir, err := n.prepareProductionDependencies()
 		if err != nil {
-			return err
+			return "", nil, 0, err
 		}
+		defer os.RemoveAll(stagingDir)
 
-		// 计算相对路径
-		relPath, err := filepath.Rel(buildDir, path)
+		dependencyEntries, err := productionDependencyEntries(stagingDir)
 		if err != nil {
-			return err
-		}
-
-		// 跳过根目录
-		if relPath == "." {
-			return nil
-		}
-
-		files = append(files, relPath)
-
-		// 创建 tar header
-		header, err := tar.FileInfoHeader(info, "")
-		if err != nil {
-			return err
-		}
-		header.Name = relPath
-
-		if err := tarWriter.WriteHeader(header); err != nil {
-			return err
+			return "", nil, 0, err
 		}
+		entries = append(entries, dependencyEntries...)
+	}
 
-		// 如果是文件，写入内容
-		if !info.IsDir() {
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
-
-			size, err := io.Copy(tarWriter, file)
-			if err != nil {
-				return err
-			}
-			totalSize += size
-		}
+	entries, err = applyArtifactSelection(n.config, n.options.ProjectPath, entries)
+	if err != nil {
+		return "", nil, 0, err
+	}
 
-		return nil
-	})
+	artifactName := fmt.Sprintf("%s-%s%s", n.config.Project.Name, n.options.Version, artifactExtension(opts.Format))
+	artifactPath := filepath.Join(outputDir, artifactName)
 
+	files, checksums, size, err := writeArtifact(artifactPath, entries, opts)
 	if err != nil {
 		return "", nil, 0, fmt.Errorf("打包文件失败: %w", err)
 	}
-
-	// 获取最终文件大小
-	if stat, err := os.Stat(artifactPath); err == nil {
-		totalSize = stat.Size()
-	}
+	n.fileChecksums = checksums
 
 	fmt.Printf("✓ 打包完成: %s\n", artifactPath)
-	return artifactPath, files, totalSize, nil
+	return artifactPath, files, size, nil
 }
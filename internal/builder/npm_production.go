@@ -0,0 +1,142 @@
+package builder
+
+import (
+	"deploy/internal/utils"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// npmDependencyFiles 安装依赖所需的项目文件
+var npmDependencyFiles = []string{
+	"package.json",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	".npmrc",
+	".yarnrc",
+	".yarnrc.yml",
+}
+
+// productionInstallCommand 获取安装生产依赖的命令，默认在 install_command 后追加对应包管理器的参数
+//
+// 临时目录中只有 package.json 和锁文件，依赖和项目自身的生命周期脚本无法执行，默认使用 --ignore-scripts；
+// Yarn 2+ 不支持 --production，使用 yarn workspaces focus --production。
+func (n *NPMBuilder) productionInstallCommand() string {
+	if n.config.NPM.ProductionInstallCommand != "" {
+		return n.config.NPM.ProductionInstallCommand
+	}
+
+	installCmd := n.installCommand()
+	switch strings.Fields(installCmd)[0] {
+	case "yarn":
+		if n.yarnBerry() {
+			return "yarn workspaces focus --production"
+		}
+		return installCmd + " --production --ignore-scripts"
+	case "pnpm":
+		return installCmd + " --prod --ignore-scripts"
+	default:
+		return installCmd + " --omit=dev --ignore-scripts"
+	}
+}
+
+// yarnBerry 检查项目是否使用 Yarn 2 及以上版本：存在 .yarnrc.yml，或 package.json 的 packageManager 为 yarn@2 及以上
+func (n *NPMBuilder) yarnBerry() bool {
+	if utils.FileExists(filepath.Join(n.options.ProjectPath, ".yarnrc.yml")) {
+		return true
+	}
+
+	data, err := os.ReadFile(filepath.Join(n.options.ProjectPath, "package.json"))
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return false
+	}
+	version, ok := strings.CutPrefix(pkg.PackageManager, "yarn@")
+	if !ok {
+		return false
+	}
+	major, _, _ := strings.Cut(version, ".")
+	number, err := strconv.Atoi(major)
+	return err == nil && number >= 2
+}
+
+// prepareProductionDependencies 在临时目录中安装生产依赖，返回临时目录
+// 调用方负责删除返回的目录
+func (n *NPMBuilder) prepareProductionDependencies() (string, error) {
+	fmt.Println("📦 准备生产依赖...")
+
+	stagingDir, err := os.MkdirTemp("", "deploy-npm-production-")
+	if err != nil {
+		return "", fmt.Errorf("创建临时目录失败: %w", err)
+	}
+
+	// 复制 package.json、锁文件和包管理器配置
+	for _, name := range npmDependencyFiles {
+		src := filepath.Join(n.options.ProjectPath, name)
+		if !utils.FileExists(src) {
+			continue
+		}
+		if err := utils.CopyFile(src, filepath.Join(stagingDir, name)); err != nil {
+			os.RemoveAll(stagingDir)
+			return "", fmt.Errorf("复制 %s 失败: %w", name, err)
+		}
+	}
+
+	installCmd := n.productionInstallCommand()
+	parts := strings.Fields(installCmd)
+	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd.Dir = stagingDir
+	cmd.Env = cacheEnv("npm_config_cache", n.cacheDir)
+
+	if n.options.Verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(stagingDir)
+		return "", fmt.Errorf("执行 %s 失败: %w", installCmd, err)
+	}
+
+	fmt.Println("✓ 生产依赖准备完成")
+	return stagingDir, nil
+}
+
+// productionDependencyEntries 收集临时目录中的 node_modules 和 package.json
+func productionDependencyEntries(stagingDir string) ([]archiveEntry, error) {
+	var entries []archiveEntry
+
+	packageJSON := filepath.Join(stagingDir, "package.json")
+	if info, err := os.Stat(packageJSON); err == nil {
+		entries = append(entries, fileEntry(packageJSON, "package.json", info))
+	}
+
+	nodeModules := filepath.Join(stagingDir, "node_modules")
+	info, err := os.Lstat(nodeModules)
+	if os.IsNotExist(err) {
+		// 没有生产依赖
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dirEntries, err := collectDirEntries(nodeModules, "node_modules")
+	if err != nil {
+		return nil, fmt.Errorf("收集 node_modules 失败: %w", err)
+	}
+
+	entries = append(entries, fileEntry(nodeModules, "node_modules", info))
+	return append(entries, dirEntries...), nil
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"deploy/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProductionInstallCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		install  string
+		files    map[string]string
+		override string
+		expected string
+	}{
+		{name: "npm", install: "", expected: "npm ci --omit=dev --ignore-scripts"},
+		{name: "pnpm", install: "pnpm install --frozen-lockfile", expected: "pnpm install --frozen-lockfile --prod --ignore-scripts"},
+		{name: "yarn classic", install: "yarn install --frozen-lockfile", expected: "yarn install --frozen-lockfile --production --ignore-scripts"},
+		{
+			name:     "yarn berry .yarnrc.yml",
+			install:  "yarn install --immutable",
+			files:    map[string]string{".yarnrc.yml": "nodeLinker: node-modules\n"},
+			expected: "yarn workspaces focus --production",
+		},
+		{
+			name:     "yarn berry packageManager",
+			install:  "yarn install --immutable",
+			files:    map[string]string{"package.json": `{"packageManager": "yarn@4.1.0"}`},
+			expected: "yarn workspaces focus --production",
+		},
+		{
+			name:     "yarn 1 packageManager",
+			install:  "yarn install",
+			files:    map[string]string{"package.json": `{"packageManager": "yarn@1.22.19"}`},
+			expected: "yarn install --production --ignore-scripts",
+		},
+		{name: "production_install_command", install: "pnpm install", override: "pnpm deploy --prod .", expected: "pnpm deploy --prod ."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cfg := &config.Config{NPM: config.NPMConfig{InstallCommand: tt.install, ProductionInstallCommand: tt.override}}
+			builder := NewNPMBuilder(cfg, &BuildOptions{ProjectPath: dir})
+
+			if command := builder.productionInstallCommand(); command != tt.expected {
+				t.Errorf("安装命令为 %q，应为 %q", command, tt.expected)
+			}
+		})
+	}
+}
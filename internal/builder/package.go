@@ -0,0 +1,109 @@
+package builder
+
+import (
+	"deploy/internal/config"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// outputDirectory 获取并创建输出目录，相对路径相对项目目录，返回绝对路径
+//
+// 构建器在项目目录中执行，构建结束后会切换回原来的工作目录，
+// 因此构建产物路径必须是绝对路径，之后保存到仓库或部署时才能找到文件。
+func outputDirectory(options *BuildOptions) (string, error) {
+	outputDir := options.OutputPath
+	if outputDir == "" {
+		outputDir = "build"
+	}
+	outputDir, err := filepath.Abs(resolveProjectFile(options.ProjectPath, outputDir))
+	if err != nil {
+		return "", fmt.Errorf("获取输出目录绝对路径失败: %w", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	return outputDir, nil
+}
+
+// resolveArchiveOptions 根据配置生成打包选项
+func resolveArchiveOptions(cfg *config.Config, options *BuildOptions, defaultFormat string, reproducible bool) (archiveOptions, error) {
+	format := cfg.Artifact.Format
+	if format == "" {
+		format = defaultFormat
+	}
+	if !IsValidArtifactFormat(format) || format == FormatJar {
+		return archiveOptions{}, fmt.Errorf("不支持的构建产物格式: %s", format)
+	}
+
+	opts := archiveOptions{
+		Format:           format,
+		CompressionLevel: DefaultCompressionLevel,
+		Reproducible:     reproducible,
+	}
+	if cfg.Artifact.CompressionLevel != nil {
+		opts.CompressionLevel = *cfg.Artifact.CompressionLevel
+	}
+
+	// 可重现模式下统一时间戳
+	if reproducible {
+		modTime, err := resolveSourceDateEpoch(options.ProjectPath)
+		if err != nil {
+			return archiveOptions{}, err
+		}
+		opts.ModTime = modTime
+		fmt.Printf("  可重现模式，时间戳: %s\n", modTime.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+
+	return opts, nil
+}
+
+// resolveProjectFile 将配置中的相对路径解析为项目目录下的路径
+func resolveProjectFile(projectPath, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(projectPath, path)
+}
+
+// compareVersions 比较 1.21.5 形式的版本号，两边都忽略 v、go 前缀以及数字之后的后缀 (例如 go1.22rc1、3.13.0b1)
+func compareVersions(a, b string) int {
+	as, bs := versionNumbers(a), versionNumbers(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionNumbers 获取版本号中每一段开头的数字，遇到非数字后缀时停止
+func versionNumbers(version string) []int {
+	version = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "go"), "v")
+
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		number, _ := strconv.Atoi(part[:end])
+		numbers = append(numbers, number)
+		if end < len(part) {
+			break
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,27 @@
+package builder
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.21.5", b: "1.21", expected: 1},
+		{a: "1.21", b: "1.21.0", expected: 0},
+		{a: "1.9", b: "1.10", expected: -1},
+		{a: "v1.22.0", b: "1.22", expected: 0},
+		{a: "1.22", b: "v1.22.0", expected: 0},
+		{a: "go1.22rc1", b: "1.22", expected: 0},
+		{a: "1.21", b: "go1.22rc1", expected: -1},
+		{a: "3.13.0b1", b: "3.12", expected: 1},
+		{a: "3.13.0b1", b: "3.13.1", expected: -1},
+		{a: "3.8", b: "3.13.0b1", expected: -1},
+	}
+
+	for _, tt := range tests {
+		if actual := compareVersions(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("compareVersions(%q, %q) = %d，应为 %d", tt.a, tt.b, actual, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package builder
+
+import (
+	"deploy/internal/cache"
+	"deploy/internal/config"
+	"deploy/internal/detector"
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// PythonModeBundle 应用代码和依赖打包为一个压缩包
+	PythonModeBundle = "bundle"
+	// PythonModeWheel 构建项目及依赖的 wheel
+	PythonModeWheel = "wheel"
+)
+
+// pythonExcludes bundle 模式下不打包的目录和文件
+var pythonExcludes = []string{".venv", "venv", "__pycache__", ".tox", ".pytest_cache", ".mypy_cache", "*.egg-info", "*.pyc"}
+
+// PythonBuilder Python 构建器
+type PythonBuilder struct {
+	config        *config.Config
+	options       *BuildOptions
+	toolchain     map[string]string
+	fileChecksums []manifest.FileChecksum
+	cacheDir      string // 依赖缓存目录，为空时使用 pip 的默认目录
+	project       *detector.PythonProjectInfo
+}
+
+// NewPythonBuilder 创建 Python 构建器
+func NewPythonBuilder(config *config.Config, options *BuildOptions) *PythonBuilder {
+	return &PythonBuilder{
+		config:    config,
+		options:   options,
+		toolchain: make(map[string]string),
+	}
+}
+
+// GetType 获取构建器类型
+func (p *PythonBuilder) GetType() detector.ProjectType {
+	return detector.ProjectTypePython
+}
+
+// Toolchain 获取工具链版本，在 Validate 之后可用
+func (p *PythonBuilder) Toolchain() map[string]string {
+	return p.toolchain
+}
+
+// Validate 验证构建环境
+func (p *PythonBuilder) Validate() error {
+	project, ok := detector.ParsePythonProject(p.options.ProjectPath)
+	if !ok {
+		return fmt.Errorf("pyproject.toml、setup.py 或 requirements.txt 不存在: %s", p.options.ProjectPath)
+	}
+	p.project = project
+
+	switch p.mode() {
+	case PythonModeBundle:
+	case PythonModeWheel:
+		if !project.HasPyproject && !project.HasSetupPy {
+			return fmt.Errorf("wheel 模式需要 pyproject.toml 或 setup.py")
+		}
+	default:
+		return fmt.Errorf("不支持的 Python 构建模式: %s (可选 bundle, wheel)", p.config.Python.Mode)
+	}
+
+	if err := p.checkPython(); err != nil {
+		return fmt.Errorf("Python 环境检查失败: %w", err)
+	}
+
+	if err := p.checkPip(); err != nil {
+		return fmt.Errorf("pip 环境检查失败: %w", err)
+	}
+
+	return nil
+}
+
+// Build 执行构建
+func (p *PythonBuilder) Build() (*BuildResult, error) {
+	startTime := time.Now()
+
+	fmt.Println("🚀 开始构建 Python 项目...")
+
+	// 生成版本号
+	if p.options.Version == "" {
+		p.options.Version = utils.GenerateVersion()
+	}
+
+	// 准备依赖缓存目录
+	cacheDir, err := prepareDependencyCache(p.config, cache.KindPip)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	p.cacheDir = cacheDir
+
+	// 切换到项目目录
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(p.options.ProjectPath); err != nil {
+		return nil, fmt.Errorf("切换到项目目录失败: %w", err)
+	}
+
+	stagingDir, err := os.MkdirTemp("", "deploy-python-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	// 安装依赖或构建 wheel
+	if err := p.runPip(stagingDir); err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("Python 构建失败: %v", err),
+		}, err
+	}
+
+	// 打包构建产物
+	artifactPath, files, size, err := p.packageArtifacts(stagingDir)
+	if err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("打包失败: %v", err),
+		}, err
+	}
+
+	result := &BuildResult{
+		Success:      true,
+		ArtifactPath: artifactPath,
+		Version:      p.options.Version,
+		Files:        files,
+		Size:         size,
+		Message:      "构建成功",
+	}
+
+	// 生成构建清单
+	if err := writeManifest(manifestInput{
+		ProjectName:  p.config.Project.Name,
+		ProjectPath:  p.options.ProjectPath,
+		Version:      p.options.Version,
+		Builder:      string(p.GetType()),
+		Toolchain:    p.toolchain,
+		BuildCommand: p.buildCommand(),
+		ArtifactPath: artifactPath,
+		Files:        p.fileChecksums,
+	}, result); err != nil {
+		return &BuildResult{
+			Success: false,
+			Message: fmt.Sprintf("生成构建清单失败: %v", err),
+		}, err
+	}
+
+	buildTime := time.Since(startTime)
+	result.BuildTime = buildTime.String()
+
+	fmt.Printf("✅ Python 项目构建完成，耗时: %v\n", buildTime)
+	fmt.Printf("📦 构建产物: %s (%.2f MB)\n", artifactPath, float64(size)/(1024*1024))
+
+	return result, nil
+}
+
+// mode 获取构建模式，默认为 bundle
+func (p *PythonBuilder) mode() string {
+	if p.config.Python.Mode == "" {
+		return PythonModeBundle
+	}
+	return p.config.Python.Mode
+}
+
+// interpreter 获取 Python 解释器
+func (p *PythonBuilder) interpreter() string {
+	if p.config.Python.Interpreter == "" {
+		return "python3"
+	}
+	return p.config.Python.Interpreter
+}
+
+// targetDir 获取 bundle 模式下依赖的安装目录
+func (p *PythonBuilder) targetDir() string {
+	if p.config.Python.TargetDir == "" {
+		return "vendor"
+	}
+	return filepath.ToSlash(filepath.Clean(p.config.Python.TargetDir))
+}
+
+// requirementsFile 获取依赖文件，不存在时返回空字符串
+func (p *PythonBuilder) requirementsFile() string {
+	requirements := p.config.Python.Requirements
+	if requirements == "" {
+		requirements = "requirements.txt"
+	}
+	if _, err := os.Stat(resolveProjectFile(p.options.ProjectPath, requirements)); err != nil {
+		return ""
+	}
+	return requirements
+}
+
+// checkPython 检查 Python 环境，版本低于要求时给出警告
+func (p *PythonBuilder) checkPython() error {
+	output, err := exec.Command(p.interpreter(), "--version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s 未安装或不在 PATH 中", p.interpreter())
+	}
+
+	version := strings.TrimSpace(string(output))
+	p.toolchain["python"] = version
+	fmt.Printf("✓ Python 版本: %s\n", version)
+
+	required := p.config.Python.PythonVersion
+	if required == "" {
+		required = minimumPythonVersion(p.project.RequiresPython)
+	}
+	if required != "" {
+		fmt.Printf("  要求版本: %s\n", required)
+		if compareVersions(strings.TrimPrefix(version, "Python "), required) < 0 {
+			utils.PrintWarning(fmt.Sprintf("当前 Python 版本 %s 低于要求的 %s", version, required))
+		}
+	}
+
+	return nil
+}
+
+// checkPip 检查 pip 环境
+func (p *PythonBuilder) checkPip() error {
+	output, err := exec.Command(p.interpreter(), "-m", "pip", "--version").Output()
+	if err != nil {
+		return fmt.Errorf("pip 未安装")
+	}
+
+	// 输出格式: pip 23.2.1 from /path/to/pip (python 3.11)
+	version := strings.TrimSpace(string(output))
+	if fields := strings.Fields(version); len(fields) >= 2 {
+		version = fields[1]
+	}
+	p.toolchain["pip"] = version
+	fmt.Printf("✓ pip 版本: %s\n", version)
+
+	return nil
+}
+
+// minimumPythonVersion 从 requires-python 中提取最低版本，例如 ">=3.9,<4" 返回 3.9
+func minimumPythonVersion(spec string) string {
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, ">=") {
+			return strings.TrimSpace(strings.TrimPrefix(part, ">="))
+		}
+	}
+	return ""
+}
+
+// pipArgs 获取 pip 参数，outputDir 为依赖安装目录或 wheel 输出目录
+func (p *PythonBuilder) pipArgs(outputDir string) []string {
+	args := []string{"-m", "pip"}
+	if p.mode() == PythonModeWheel {
+		args = append(args, "wheel", "--wheel-dir", outputDir)
+	} else {
+		args = append(args, "install", "--no-compile", "--target", outputDir)
+	}
+	args = append(args, p.config.Python.PipArgs...)
+
+	if requirements := p.requirementsFile(); requirements != "" && p.mode() == PythonModeBundle {
+		return append(args, "-r", requirements)
+	}
+	return append(args, ".")
+}
+
+// buildCommand 描述构建命令
+func (p *PythonBuilder) buildCommand() string {
+	outputDir := p.targetDir()
+	if p.mode() == PythonModeWheel {
+		outputDir = "wheels"
+	}
+	return p.interpreter() + " " + strings.Join(p.pipArgs(outputDir), " ")
+}
+
+// runPip 执行 pip install 或 pip wheel
+func (p *PythonBuilder) runPip(stagingDir string) error {
+	outputDir := filepath.Join(stagingDir, filepath.FromSlash(p.targetDir()))
+	if p.mode() == PythonModeWheel {
+		outputDir = filepath.Join(stagingDir, "wheels")
+		fmt.Println("🔨 构建 wheel...")
+	} else {
+		fmt.Println("📥 安装依赖...")
+	}
+
+	cmd := exec.Command(p.interpreter(), p.pipArgs(outputDir)...)
+	cmd.Env = cacheEnv("PIP_CACHE_DIR", p.cacheDir)
+
+	if p.options.Verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pip 执行失败: %w", err)
+	}
+
+	fmt.Println("✓ pip 执行完成")
+	return nil
+}
+
+// sourceEntries 收集 bundle 模式下的应用代码
+func (p *PythonBuilder) sourceEntries() ([]archiveEntry, error) {
+	files, err := utils.GitListFiles(p.options.ProjectPath)
+	if err != nil {
+		files, err = walkSourceFiles(p.options.ProjectPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	excludes := append(cacheExcludes(p.config, p.options, detector.ProjectTypePython), p.targetDir())
+
+	var entries []archiveEntry
+	for _, file := range files {
+		file = filepath.ToSlash(file)
+		if isCacheExcluded(file, excludes) || isPythonExcluded(file) {
+			continue
+		}
+
+		path := filepath.Join(p.options.ProjectPath, filepath.FromSlash(file))
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			// 已删除但尚未提交的文件
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		entries = append(entries, fileEntry(path, file, info))
+	}
+
+	return entries, nil
+}
+
+// isPythonExcluded 判断文件是否为虚拟环境、字节码等不需要打包的文件
+func isPythonExcluded(file string) bool {
+	for _, segment := range strings.Split(file, "/") {
+		for _, pattern := range pythonExcludes {
+			if matched, _ := filepath.Match(pattern, segment); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// packageArtifacts 打包构建产物
+func (p *PythonBuilder) packageArtifacts(stagingDir string) (string, []string, int64, error) {
+	fmt.Println("📦 打包构建产物...")
+
+	outputDir, err := outputDirectory(p.options)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	opts, err := resolveArchiveOptions(p.config, p.options, FormatTarGz, p.options.Reproducible)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	entries, err := collectDirEntries(stagingDir, "")
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("遍历构建目录失败: %w", err)
+	}
+
+	if p.mode() == PythonModeBundle {
+		sources, err := p.sourceEntries()
+		if err != nil {
+			return "", nil, 0, fmt.Errorf("收集应用代码失败: %w", err)
+		}
+		entries = append(entries, sources...)
+	}
+
+	entries, err = applyArtifactSelection(p.config, p.options.ProjectPath, entries)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	artifactName := fmt.Sprintf("%s-%s%s", p.config.Project.Name, p.options.Version, artifactExtension(opts.Format))
+	artifactPath := filepath.Join(outputDir, artifactName)
+
+	files, checksums, size, err := writeArtifact(artifactPath, entries, opts)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("打包文件失败: %w", err)
+	}
+	p.fileChecksums = checksums
+
+	fmt.Printf("✓ 打包完成: %s\n", artifactPath)
+	return artifactPath, files, size, nil
+}
@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"deploy/internal/utils"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// resolveSourceDateEpoch 获取可重现构建使用的时间戳
+// 优先使用 SOURCE_DATE_EPOCH 环境变量，其次使用最近一次 Git 提交时间
+func resolveSourceDateEpoch(projectPath string) (time.Time, error) {
+	if value := strings.TrimSpace(os.Getenv("SOURCE_DATE_EPOCH")); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("SOURCE_DATE_EPOCH 无效: %s", value)
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	commitTime, err := utils.GetGitCommitTime(projectPath)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("可重现模式需要设置 SOURCE_DATE_EPOCH 或在 Git 仓库中构建: %w", err)
+	}
+
+	return commitTime.UTC(), nil
+}
+
+// normalizeEntry 规范化条目的时间戳和权限，去除与构建环境相关的信息
+func normalizeEntry(entry *archiveEntry, modTime time.Time) {
+	entry.ModTime = modTime
+
+	// 目录和可执行文件统一为 0755，其余文件统一为 0644
+	switch {
+	case entry.Mode.IsDir():
+		entry.Mode = os.ModeDir | 0755
+	case entry.isSymlink():
+		entry.Mode = os.ModeSymlink | 0777
+	case entry.Mode&0111 != 0:
+		entry.Mode = 0755
+	default:
+		entry.Mode = 0644
+	}
+}
+
+// normalizeGzipHeader 使用固定的 gzip header
+func normalizeGzipHeader(header *gzip.Header) {
+	header.Name = ""
+	header.Comment = ""
+	header.Extra = nil
+	header.ModTime = time.Time{}
+	header.OS = 255 // unknown
+}
+
+// copyWithChecksum 将 r 的内容写入 w，同时计算 SHA-256
+func copyWithChecksum(w io.Writer, r io.Reader) (string, int64, error) {
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(w, hash), r)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
@@ -0,0 +1,118 @@
+package builder
+
+import (
+	"bytes"
+	"deploy/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSourceTree 创建内容固定的源目录，文件的修改时间和权限由参数指定
+func writeSourceTree(t *testing.T, modTime time.Time, fileMode os.FileMode) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"index.html":        "<html></html>",
+		"assets/app.js":     "console.log('app')",
+		"assets/style.css":  "body {}",
+		"bin/start.sh":      "#!/bin/sh\necho start",
+		"nested/deep/a.txt": "a",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		mode := fileMode
+		if filepath.Ext(name) == ".sh" {
+			mode |= 0111
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 修改时间放在最后，目录的修改时间不受创建文件影响
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(path, modTime, modTime)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// buildReproducible 按可重现模式打包源目录，返回构建产物内容
+func buildReproducible(t *testing.T, format string, source string, reverse bool) []byte {
+	t.Helper()
+
+	cfg := &config.Config{Artifact: config.ArtifactConfig{Format: format}}
+	opts, err := resolveArchiveOptions(cfg, &BuildOptions{ProjectPath: source}, FormatTarGz, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := collectDirEntries(source, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 条目顺序不应影响结果
+	if reverse {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+
+	artifactPath := filepath.Join(t.TempDir(), "app"+artifactExtension(format))
+	if _, _, _, err := writeArtifact(artifactPath, entries, opts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(artifactPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestWriteArtifactReproducible(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	for _, format := range []string{FormatTarGz, FormatZip} {
+		t.Run(format, func(t *testing.T) {
+			first := writeSourceTree(t, time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), 0644)
+			second := writeSourceTree(t, time.Date(2024, 6, 7, 8, 9, 10, 0, time.UTC), 0600)
+
+			a := buildReproducible(t, format, first, false)
+			b := buildReproducible(t, format, second, true)
+			if len(a) == 0 {
+				t.Fatal("构建产物为空")
+			}
+			if !bytes.Equal(a, b) {
+				t.Fatalf("两次构建的产物不一致: %d 字节 / %d 字节", len(a), len(b))
+			}
+		})
+	}
+}
+
+func TestWriteArtifactReproducibleEpoch(t *testing.T) {
+	source := writeSourceTree(t, time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), 0644)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	a := buildReproducible(t, FormatTarGz, source, false)
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000001")
+	b := buildReproducible(t, FormatTarGz, source, false)
+
+	// 时间戳不同时产物应不同，确认 SOURCE_DATE_EPOCH 确实写入了构建产物
+	if bytes.Equal(a, b) {
+		t.Fatal("SOURCE_DATE_EPOCH 不同时构建产物不应相同")
+	}
+}
@@ -0,0 +1,151 @@
+package builder
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// hasArtifactSelection 是否配置了 include 或 extra_files
+func hasArtifactSelection(cfg *config.Config) bool {
+	return len(cfg.Artifact.Include) > 0 || len(cfg.Artifact.ExtraFiles) > 0
+}
+
+// applyArtifactSelection 根据 include、extra_files 和 exclude 配置调整待打包的条目
+func applyArtifactSelection(cfg *config.Config, projectPath string, entries []archiveEntry) ([]archiveEntry, error) {
+	artifact := cfg.Artifact
+
+	for _, pattern := range append(append([]string{}, artifact.Include...), artifact.Exclude...) {
+		if err := utils.ValidateGlob(pattern); err != nil {
+			return nil, fmt.Errorf("glob 模式无效 %q: %w", pattern, err)
+		}
+	}
+
+	// 额外包含的项目文件
+	for _, pattern := range artifact.Include {
+		included, err := collectIncludedEntries(projectPath, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(included) == 0 {
+			utils.PrintWarning(fmt.Sprintf("include 模式未匹配到任何文件: %s", pattern))
+		}
+		entries = append(entries, included...)
+	}
+
+	// 额外文件映射
+	for _, extra := range artifact.ExtraFiles {
+		mapped, err := collectExtraEntries(projectPath, extra)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapped...)
+	}
+
+	// 排除文件，同名条目以后添加的为准
+	seen := make(map[string]int)
+	var result []archiveEntry
+	for _, entry := range entries {
+		if isExcluded(artifact.Exclude, entry.Name) {
+			continue
+		}
+		if i, ok := seen[entry.Name]; ok {
+			result[i] = entry
+			continue
+		}
+		seen[entry.Name] = len(result)
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// isExcluded 检查压缩包内路径是否被排除，目录被排除时其中的文件也被排除
+func isExcluded(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		for p := name; p != "." && p != ""; p = path.Dir(p) {
+			if utils.MatchGlob(pattern, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectIncludedEntries 收集项目中匹配 include 模式的文件，匹配到目录时包含整个目录
+func collectIncludedEntries(projectPath, pattern string) ([]archiveEntry, error) {
+	root := filepath.Join(projectPath, filepath.FromSlash(utils.GlobBase(pattern)))
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	// 除非模式中显式指定，否则不进入 .git 和 node_modules
+	searchable := func(name string) bool {
+		return name != ".git" && (name != "node_modules" || strings.Contains(pattern, "node_modules"))
+	}
+
+	var entries []archiveEntry
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(projectPath, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(relPath)
+
+		if info.IsDir() && p != root && !searchable(info.Name()) {
+			return filepath.SkipDir
+		}
+		if !utils.MatchGlob(pattern, name) {
+			return nil
+		}
+
+		entries = append(entries, fileEntry(p, name, info))
+		if !info.IsDir() {
+			return nil
+		}
+
+		dirEntries, err := collectDirEntries(p, name)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, dirEntries...)
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("收集 %s 匹配的文件失败: %w", pattern, err)
+	}
+
+	return entries, nil
+}
+
+// collectExtraEntries 按 source→destination 映射收集文件
+func collectExtraEntries(projectPath string, extra config.ExtraFile) ([]archiveEntry, error) {
+	source := resolveProjectFile(projectPath, extra.Source)
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("额外文件不存在: %s", extra.Source)
+	}
+
+	destination := strings.Trim(filepath.ToSlash(extra.Destination), "/")
+	if destination == "" {
+		destination = filepath.Base(source)
+	}
+
+	if !info.IsDir() {
+		return []archiveEntry{fileEntry(source, destination, info)}, nil
+	}
+
+	entries, err := collectDirEntries(source, destination)
+	if err != nil {
+		return nil, fmt.Errorf("收集额外目录失败: %w", err)
+	}
+	return append([]archiveEntry{fileEntry(source, destination, info)}, entries...), nil
+}
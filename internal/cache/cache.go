@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Kind 依赖缓存类型
+type Kind string
+
+const (
+	KindNPM    Kind = "npm"
+	KindMaven  Kind = "maven"
+	KindGradle Kind = "gradle"
+	KindGo     Kind = "go"
+	KindPip    Kind = "pip"
+)
+
+// Kinds 所有依赖缓存类型
+var Kinds = []Kind{KindNPM, KindMaven, KindGradle, KindGo, KindPip}
+
+const (
+	// ScopeGlobal 所有项目共享缓存
+	ScopeGlobal = "global"
+	// ScopeProject 每个项目单独缓存
+	ScopeProject = "project"
+)
+
+// Dir 依赖缓存目录
+type Dir struct {
+	Kind Kind
+	Path string
+}
+
+// DefaultRoot 获取默认缓存根目录，优先使用 DEPLOY_CACHE_DIR，其次为 DEPLOY_HOME/cache
+func DefaultRoot() string {
+	if dir := os.Getenv("DEPLOY_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	if home := os.Getenv("DEPLOY_HOME"); home != "" {
+		return filepath.Join(home, "cache")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".deploy", "cache")
+	}
+	return filepath.Join(".deploy", "cache")
+}
+
+// Enabled 是否启用依赖缓存
+func Enabled(cfg config.CacheConfig) bool {
+	return cfg.Enabled || os.Getenv("DEPLOY_CACHE_DIR") != ""
+}
+
+// Validate 验证缓存配置
+func Validate(cfg config.CacheConfig) error {
+	switch cfg.Scope {
+	case "", ScopeGlobal, ScopeProject:
+		return nil
+	default:
+		return fmt.Errorf("不支持的缓存范围: %s (可选 global, project)", cfg.Scope)
+	}
+}
+
+// Path 获取指定类型的缓存目录
+func Path(cfg config.CacheConfig, project string, kind Kind) string {
+	switch kind {
+	case KindNPM:
+		if cfg.NPM != "" {
+			return cfg.NPM
+		}
+	case KindMaven:
+		if cfg.Maven != "" {
+			return cfg.Maven
+		}
+	case KindGradle:
+		if cfg.Gradle != "" {
+			return cfg.Gradle
+		}
+	case KindGo:
+		if cfg.Go != "" {
+			return cfg.Go
+		}
+	case KindPip:
+		if cfg.Pip != "" {
+			return cfg.Pip
+		}
+	}
+
+	root := cfg.Dir
+	if root == "" {
+		root = DefaultRoot()
+	}
+	if cfg.Scope == ScopeProject && project != "" {
+		return filepath.Join(root, "projects", utils.SanitizeFileName(project), string(kind))
+	}
+	return filepath.Join(root, string(kind))
+}
+
+// Prepare 创建并返回指定类型的缓存目录，未启用缓存时返回空字符串
+func Prepare(cfg config.CacheConfig, project string, kind Kind) (string, error) {
+	if !Enabled(cfg) {
+		return "", nil
+	}
+	if err := Validate(cfg); err != nil {
+		return "", err
+	}
+
+	dir, err := filepath.Abs(Path(cfg, project, kind))
+	if err != nil {
+		return "", fmt.Errorf("获取缓存目录失败: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	return dir, nil
+}
+
+// Dirs 获取所有类型的缓存目录
+func Dirs(cfg config.CacheConfig, project string) []Dir {
+	dirs := make([]Dir, 0, len(Kinds))
+	for _, kind := range Kinds {
+		dirs = append(dirs, Dir{Kind: kind, Path: Path(cfg, project, kind)})
+	}
+	return dirs
+}
+
+// Size 统计目录的大小和文件数，目录不存在时返回 0
+func Size(dir string) (int64, int, error) {
+	var size int64
+	var files int
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+			files++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("统计 %s 失败: %w", dir, err)
+	}
+	return size, files, nil
+}
+
+// Clean 清空缓存目录
+func Clean(dir string) error {
+	// Go 模块缓存中的目录是只读的，删除前需要恢复写权限
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && info.Mode().Perm()&0200 == 0 {
+			os.Chmod(path, info.Mode().Perm()|0200)
+		}
+		return nil
+	})
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("清理 %s 失败: %w", dir, err)
+	}
+	return nil
+}
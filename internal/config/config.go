@@ -1,115 +1,352 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
-	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // Config 主配置结构
 type Config struct {
-	Project      ProjectConfig                `yaml:"project"`
-	NPM          NPMConfig                    `yaml:"npm"`
-	Java         JavaConfig                   `yaml:"java"`
-	Scripts      ScriptsConfig                `yaml:"scripts"`
-	Environments map[string]EnvironmentConfig `yaml:"environments"`
-	Deploy       DeployConfig                 `yaml:"deploy"`
+	Version      int                          `yaml:"version,omitempty"` // 配置文件版本，见 CurrentVersion
+	Project      ProjectConfig                `yaml:"project,omitempty"`
+	NPM          NPMConfig                    `yaml:"npm,omitempty"`
+	Java         JavaConfig                   `yaml:"java,omitempty"`
+	Go           GoConfig                     `yaml:"go,omitempty"`
+	Python       PythonConfig                 `yaml:"python,omitempty"`
+	Scripts      ScriptsConfig                `yaml:"scripts,omitempty"`
+	Environments map[string]EnvironmentConfig `yaml:"environments,omitempty"`
+	Deploy       DeployConfig                 `yaml:"deploy,omitempty"`
+	Artifact     ArtifactConfig               `yaml:"artifact,omitempty"`
+	Publish      PublishConfig                `yaml:"publish,omitempty"`
+	Cache        CacheConfig                  `yaml:"cache,omitempty"`
+	Docker       DockerConfig                 `yaml:"docker,omitempty"`
+	Secrets      SecretsConfig                `yaml:"secrets,omitempty"`
+	SSH          SSHConfig                    `yaml:"ssh,omitempty"`
 }
 
 // ProjectConfig 项目配置
 type ProjectConfig struct {
-	Name string `yaml:"name"`
-	Type string `yaml:"type"` // auto, npm, maven, gradle
+	Name string `yaml:"name,omitempty"`
+	Type string `yaml:"type,omitempty"` // auto, npm, maven, gradle
 }
 
 // NPMConfig NPM项目配置
 type NPMConfig struct {
-	BuildCommand        string `yaml:"build_command"`
-	BuildDir            string `yaml:"build_dir"`
-	InstallCommand      string `yaml:"install_command"`
-	NodeVersion         string `yaml:"node_version"`
-	DefaultStartCommand string `yaml:"default_start_command"`
-	DefaultStopCommand  string `yaml:"default_stop_command"`
+	BuildCommand             string `yaml:"build_command,omitempty"`
+	BuildDir                 string `yaml:"build_dir,omitempty"`
+	InstallCommand           string `yaml:"install_command,omitempty"`
+	NodeVersion              string `yaml:"node_version,omitempty"`
+	DefaultStartCommand      string `yaml:"default_start_command,omitempty"`
+	DefaultStopCommand       string `yaml:"default_stop_command,omitempty"`
+	Reproducible             bool   `yaml:"reproducible,omitempty"`               // 生成可重现（逐字节一致）的压缩包
+	ProductionDependencies   bool   `yaml:"production_dependencies,omitempty"`    // 将生产依赖与构建产物一起打包
+	ProductionInstallCommand string `yaml:"production_install_command,omitempty"` // 为空时根据 install_command 推断
 }
 
 // JavaConfig Java项目配置
 type JavaConfig struct {
-	BuildTool            string      `yaml:"build_tool"`
-	BuildCommand         string      `yaml:"build_command"`
-	ArtifactPath         string      `yaml:"artifact_path"`
-	JavaVersion          string      `yaml:"java_version"`
-	Runtime              JavaRuntime `yaml:"runtime"`
-	DefaultStartCommand  string      `yaml:"default_start_command"`
-	DefaultStopCommand   string      `yaml:"default_stop_command"`
-	DefaultStatusCommand string      `yaml:"default_status_command"`
+	BuildTool            string      `yaml:"build_tool,omitempty"`
+	BuildCommand         string      `yaml:"build_command,omitempty"`
+	ArtifactPath         string      `yaml:"artifact_path,omitempty"`
+	JavaVersion          string      `yaml:"java_version,omitempty"`
+	Runtime              JavaRuntime `yaml:"runtime,omitempty"`
+	DefaultStartCommand  string      `yaml:"default_start_command,omitempty"`
+	DefaultStopCommand   string      `yaml:"default_stop_command,omitempty"`
+	DefaultStatusCommand string      `yaml:"default_status_command,omitempty"`
 }
 
 // JavaRuntime Java运行时配置
 type JavaRuntime struct {
-	HeapSize   HeapSize `yaml:"heap_size"`
-	JvmOptions []string `yaml:"jvm_options"`
-	AppOptions []string `yaml:"app_options"`
+	HeapSize   HeapSize `yaml:"heap_size,omitempty"`
+	JvmOptions []string `yaml:"jvm_options,omitempty"`
+	AppOptions []string `yaml:"app_options,omitempty"`
 }
 
 // HeapSize 堆内存配置
 type HeapSize struct {
-	Min string `yaml:"min"`
-	Max string `yaml:"max"`
+	Min string `yaml:"min,omitempty"`
+	Max string `yaml:"max,omitempty"`
+}
+
+// GoConfig Go 项目配置
+type GoConfig struct {
+	GoVersion       string   `yaml:"go_version,omitempty"`       // 要求的最低 Go 版本，默认使用 go.mod 中的版本
+	Packages        []string `yaml:"packages,omitempty"`         // 要构建的 main 包，默认为检测到的项目根目录和 cmd/ 下的 main 包
+	Platforms       []string `yaml:"platforms,omitempty"`        // GOOS/GOARCH 列表，例如 linux/amd64，默认为当前平台
+	LDFlags         string   `yaml:"ldflags,omitempty"`          // 额外的 -ldflags，例如 -s -w
+	VersionVariable string   `yaml:"version_variable,omitempty"` // 通过 -X 写入版本号的变量，默认为 main.version
+	Tags            []string `yaml:"tags,omitempty"`             // 构建标签
+	CGO             bool     `yaml:"cgo,omitempty"`              // 启用 CGO，默认 CGO_ENABLED=0
+}
+
+// PythonConfig Python 项目配置
+type PythonConfig struct {
+	PythonVersion string   `yaml:"python_version,omitempty"` // 要求的最低 Python 版本，默认使用 pyproject.toml 中的 requires-python
+	Interpreter   string   `yaml:"interpreter,omitempty"`    // Python 解释器，默认为 python3
+	Mode          string   `yaml:"mode,omitempty"`           // bundle: 应用代码和依赖打包 (默认)；wheel: 构建 wheel 及依赖的 wheel
+	Requirements  string   `yaml:"requirements,omitempty"`   // 依赖文件，默认为 requirements.txt，不存在时安装项目本身
+	TargetDir     string   `yaml:"target_dir,omitempty"`     // bundle 模式下依赖的安装目录，默认为 vendor
+	PipArgs       []string `yaml:"pip_args,omitempty"`       // 额外的 pip 参数，例如 --index-url
 }
 
 // ScriptsConfig 脚本配置
 type ScriptsConfig struct {
-	Global GlobalScriptConfig `yaml:"global"`
-	Custom map[string]string  `yaml:"custom"`
-	Hooks  map[string]string  `yaml:"hooks"`
+	Global GlobalScriptConfig `yaml:"global,omitempty"`
+	Custom map[string]string  `yaml:"custom,omitempty"`
+	Hooks  map[string]string  `yaml:"hooks,omitempty"`
 }
 
 // GlobalScriptConfig 全局脚本配置
 type GlobalScriptConfig struct {
-	Timeout    int    `yaml:"timeout"`
-	Shell      string `yaml:"shell"`
-	WorkingDir string `yaml:"working_dir"`
+	Timeout    int    `yaml:"timeout,omitempty"`
+	Shell      string `yaml:"shell,omitempty"`
+	WorkingDir string `yaml:"working_dir,omitempty"`
 }
 
 // EnvironmentConfig 环境配置
 type EnvironmentConfig struct {
-	Servers        []ServerConfig     `yaml:"servers"`
-	DeployPath     string             `yaml:"deploy_path"`
-	ServiceName    string             `yaml:"service_name"`
-	ServicePort    int                `yaml:"service_port"`
-	HealthCheckURL string             `yaml:"health_check_url"`
-	Scripts        EnvironmentScripts `yaml:"scripts"`
-	Java           *JavaConfig        `yaml:"java,omitempty"`
+	Servers        []ServerConfig     `yaml:"servers,omitempty"`
+	DeployPath     string             `yaml:"deploy_path,omitempty"`
+	ServiceName    string             `yaml:"service_name,omitempty"`
+	ServicePort    int                `yaml:"service_port,omitempty"`
+	HealthCheckURL string             `yaml:"health_check_url,omitempty"`
+	Scripts        EnvironmentScripts `yaml:"scripts,omitempty"`
+	Mode           string             `yaml:"mode,omitempty"`    // 部署模式，static 为静态站点，service 为 Java/Node 服务
+	Static         StaticConfig       `yaml:"static,omitempty"`  // 静态站点部署配置，mode 为 static 时使用
+	Service        ServiceConfig      `yaml:"service,omitempty"` // 服务管理配置，mode 为 service 时使用
+
+	// 覆盖顶层配置，加载环境配置时与顶层配置深度合并
+	NPM      *NPMConfig      `yaml:"npm,omitempty"`
+	Java     *JavaConfig     `yaml:"java,omitempty"`
+	Go       *GoConfig       `yaml:"go,omitempty"`
+	Python   *PythonConfig   `yaml:"python,omitempty"`
+	Deploy   *DeployConfig   `yaml:"deploy,omitempty"`
+	Artifact *ArtifactConfig `yaml:"artifact,omitempty"`
+	Docker   *DockerConfig   `yaml:"docker,omitempty"`
+	Cache    *CacheConfig    `yaml:"cache,omitempty"`
+	Publish  *PublishConfig  `yaml:"publish,omitempty"`
+	Secrets  *SecretsConfig  `yaml:"secrets,omitempty"`
+}
+
+// ServiceConfig 服务管理配置
+type ServiceConfig struct {
+	Manager string        `yaml:"manager,omitempty"` // nohup (默认，使用 default_start_command 等命令)、systemd 或 pm2
+	Systemd SystemdConfig `yaml:"systemd,omitempty"`
+	PM2     PM2Config     `yaml:"pm2,omitempty"`
+}
+
+// PM2Config PM2 ecosystem 配置
+type PM2Config struct {
+	Script           string `yaml:"script,omitempty"`             // 启动脚本，默认为 npm
+	Args             string `yaml:"args,omitempty"`               // 脚本参数，script 为 npm 时默认为 start
+	Instances        string `yaml:"instances,omitempty"`          // 实例数，默认为 1，max 表示每个 CPU 一个实例
+	ExecMode         string `yaml:"exec_mode,omitempty"`          // fork (默认) 或 cluster，cluster 模式下 reload 不中断服务
+	MaxMemoryRestart string `yaml:"max_memory_restart,omitempty"` // 内存超过该值时重启，例如 512M
+	LogDir           string `yaml:"log_dir,omitempty"`            // 日志目录，默认为 <deploy_path>/logs
+}
+
+// SystemdConfig systemd 服务配置
+type SystemdConfig struct {
+	Scope     string `yaml:"scope,omitempty"`      // system (默认) 或 user
+	UnitDir   string `yaml:"unit_dir,omitempty"`   // unit 文件目录，默认为 /etc/systemd/system 或 ~/.config/systemd/user
+	User      string `yaml:"user,omitempty"`       // 运行服务的用户，仅 system 范围有效
+	Restart   string `yaml:"restart,omitempty"`    // Restart 策略，默认为 on-failure
+	ExecStart string `yaml:"exec_start,omitempty"` // 启动命令，默认根据 java.runtime 生成或使用 npm start
+}
+
+// StaticConfig 静态站点部署配置
+type StaticConfig struct {
+	WebRoot      string      `yaml:"web_root,omitempty"`      // nginx 使用的站点目录（符号链接），默认为 <deploy_path>/current
+	ReleasesDir  string      `yaml:"releases_dir,omitempty"`  // 各版本的解压目录，默认为 <deploy_path>/releases
+	KeepReleases int         `yaml:"keep_releases,omitempty"` // 保留的旧版本数，默认为 deploy.backup_count
+	Nginx        NginxConfig `yaml:"nginx,omitempty"`
+}
+
+// NginxConfig nginx 配置
+type NginxConfig struct {
+	ConfigPath    string `yaml:"config_path,omitempty"`    // 生成的 server 配置路径，例如 /etc/nginx/conf.d/my-app.conf，为空时不生成
+	Template      string `yaml:"template,omitempty"`       // server 配置模板（相对项目目录），为空时使用内置模板
+	ServerName    string `yaml:"server_name,omitempty"`    // server_name，默认为 _
+	Listen        int    `yaml:"listen,omitempty"`         // 监听端口，默认为 service_port 或 80
+	Reload        bool   `yaml:"reload,omitempty"`         // 不生成配置时也在切换后重新加载 nginx
+	TestCommand   string `yaml:"test_command,omitempty"`   // 默认为 nginx -t
+	ReloadCommand string `yaml:"reload_command,omitempty"` // 默认为 nginx -s reload
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Host    string `yaml:"host"`
-	User    string `yaml:"user"`
-	Port    int    `yaml:"port"`
-	KeyFile string `yaml:"key_file"`
+	Host    string `yaml:"host,omitempty"`
+	User    string `yaml:"user,omitempty"`
+	Port    int    `yaml:"port,omitempty"`
+	KeyFile string `yaml:"key_file,omitempty"`
 }
 
 // EnvironmentScripts 环境脚本配置
 type EnvironmentScripts struct {
-	Deploy    string            `yaml:"deploy"`
-	Variables map[string]string `yaml:"variables"`
+	Deploy    string            `yaml:"deploy,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty"`
+
+	// 覆盖顶层 scripts 中的对应配置
+	Global *GlobalScriptConfig `yaml:"global,omitempty"`
+	Custom map[string]string   `yaml:"custom,omitempty"`
+	Hooks  map[string]string   `yaml:"hooks,omitempty"`
 }
 
 // DeployConfig 部署配置
 type DeployConfig struct {
-	BackupCount        int  `yaml:"backup_count"`
-	Timeout            int  `yaml:"timeout"`
-	RestartDelay       int  `yaml:"restart_delay"`
-	HealthCheckTimeout int  `yaml:"health_check_timeout"`
-	UseScripts         bool `yaml:"use_scripts"`
-	FallbackToDefault  bool `yaml:"fallback_to_default"`
+	BackupCount        int  `yaml:"backup_count,omitempty"`
+	Timeout            int  `yaml:"timeout,omitempty"`
+	RestartDelay       int  `yaml:"restart_delay,omitempty"`
+	HealthCheckTimeout int  `yaml:"health_check_timeout,omitempty"`
+	UseScripts         bool `yaml:"use_scripts,omitempty"`
+	FallbackToDefault  bool `yaml:"fallback_to_default,omitempty"`
+}
+
+// ArtifactConfig 构建产物配置
+type ArtifactConfig struct {
+	Format           string       `yaml:"format,omitempty"`            // tar.gz, tar.zst, zip, dir, jar
+	CompressionLevel *int         `yaml:"compression_level,omitempty"` // 未设置时使用默认级别，gzip/zip 为 0 时只存储不压缩
+	Include          []string     `yaml:"include,omitempty"`           // 额外打包的项目文件（glob，相对项目目录）
+	Exclude          []string     `yaml:"exclude,omitempty"`           // 排除的文件（glob，相对压缩包根目录）
+	ExtraFiles       []ExtraFile  `yaml:"extra_files,omitempty"`
+	Bundle           BundleConfig `yaml:"bundle,omitempty"`
+	Store            StoreConfig  `yaml:"store,omitempty"`
 }
 
-// LoadConfig 加载配置文件
+// StoreConfig 本地构建产物仓库配置
+type StoreConfig struct {
+	Path     string   `yaml:"path,omitempty"`      // 仓库目录，默认为 ~/.deploy/artifacts
+	KeepLast int      `yaml:"keep_last,omitempty"` // 构建后自动清理，只保留最新的 N 个版本（带标签的版本除外），0 表示不清理
+	S3       S3Config `yaml:"s3,omitempty"`        // 远程仓库，配置 bucket 后构建产物会自动上传
+}
+
+// S3Config S3 兼容对象存储配置 (AWS S3、MinIO 等)
+type S3Config struct {
+	Endpoint         string `yaml:"endpoint,omitempty"`           // 服务地址，例如 https://minio.example.com，为空时使用 AWS S3
+	Region           string `yaml:"region,omitempty"`             // 区域，默认为 us-east-1
+	Bucket           string `yaml:"bucket,omitempty"`             // 存储桶
+	Prefix           string `yaml:"prefix,omitempty"`             // 对象前缀
+	AccessKey        string `yaml:"access_key,omitempty"`         // 为空时使用 AWS_ACCESS_KEY_ID 环境变量
+	SecretKey        string `yaml:"secret_key,omitempty"`         // 为空时使用 AWS_SECRET_ACCESS_KEY 环境变量
+	VirtualHostStyle bool   `yaml:"virtual_host_style,omitempty"` // 使用 bucket.endpoint 形式的地址，默认使用路径形式
+	PartSize         int    `yaml:"part_size,omitempty"`          // 分片上传的分片大小 (MB)，默认为 16
+}
+
+// ExtraFile 额外打包的文件映射
+type ExtraFile struct {
+	Source      string `yaml:"source,omitempty"`      // 项目中的文件或目录
+	Destination string `yaml:"destination,omitempty"` // 压缩包内的路径，为空时使用源文件名
+}
+
+// BundleConfig Java 部署包配置
+type BundleConfig struct {
+	Enabled             bool     `yaml:"enabled,omitempty"`
+	Scripts             []string `yaml:"scripts,omitempty"`              // 放入 bin/ 的启动脚本，为空时根据默认命令生成
+	ConfigFiles         []string `yaml:"config_files,omitempty"`         // 放入 config/ 的配置文件或目录
+	ApplicationTemplate string   `yaml:"application_template,omitempty"` // 渲染为 config/application.yml 的模板
+}
+
+// PublishConfig 发布配置
+type PublishConfig struct {
+	Maven MavenPublishConfig `yaml:"maven,omitempty"`
+	NPM   NPMPublishConfig   `yaml:"npm,omitempty"`
+}
+
+// MavenPublishConfig Maven 仓库发布配置
+type MavenPublishConfig struct {
+	URL        string `yaml:"url,omitempty"` // 仓库地址，例如 https://nexus.example.com/repository/maven-releases
+	Username   string `yaml:"username,omitempty"`
+	Password   string `yaml:"password,omitempty"`
+	GroupID    string `yaml:"group_id,omitempty"`
+	ArtifactID string `yaml:"artifact_id,omitempty"` // 默认为项目名称
+}
+
+// NPMPublishConfig npm 仓库发布配置
+type NPMPublishConfig struct {
+	Registry string `yaml:"registry,omitempty"` // 默认为 https://registry.npmjs.org
+	Token    string `yaml:"token,omitempty"`
+	Mode     string `yaml:"mode,omitempty"`   // package: 发布 npm pack 生成的包；artifact: 发布构建产物
+	Tag      string `yaml:"tag,omitempty"`    // dist-tag，默认为 latest
+	Access   string `yaml:"access,omitempty"` // public 或 restricted
+}
+
+// DockerConfig Docker 镜像构建配置
+type DockerConfig struct {
+	CLI        string            `yaml:"cli,omitempty"`        // docker, podman, buildah，为空时自动检测
+	Dockerfile string            `yaml:"dockerfile,omitempty"` // 默认为项目目录中的 Dockerfile，不存在时根据项目类型生成
+	Context    string            `yaml:"context,omitempty"`    // 使用已有 Dockerfile 时的构建上下文，默认为项目目录
+	Image      string            `yaml:"image,omitempty"`      // 镜像名称，例如 registry.example.com/team/app，默认为项目名称
+	Tags       []string          `yaml:"tags,omitempty"`       // 额外的标签，例如 latest
+	BuildArgs  map[string]string `yaml:"build_args,omitempty"`
+	Platform   string            `yaml:"platform,omitempty"`   // 例如 linux/amd64
+	BaseImage  string            `yaml:"base_image,omitempty"` // 生成 Dockerfile 时使用的基础镜像
+	Port       int               `yaml:"port,omitempty"`       // 生成 Dockerfile 时暴露的端口，默认 NPM 为 3000，Java 为 8080
+	Push       bool              `yaml:"push,omitempty"`       // 构建后推送到镜像仓库
+	Save       bool              `yaml:"save,omitempty"`       // 将镜像导出为 tar 作为构建产物
+}
+
+// SSHConfig 连接服务器的默认值，服务器未配置时使用，通常写在用户级配置中
+type SSHConfig struct {
+	User    string `yaml:"user,omitempty"`
+	Port    int    `yaml:"port,omitempty"`
+	KeyFile string `yaml:"key_file,omitempty"`
+}
+
+// applySSHDefaults 为未配置用户、端口、密钥文件的服务器填入 ssh 中的默认值
+func (c *Config) applySSHDefaults() {
+	for name, env := range c.Environments {
+		for i := range env.Servers {
+			server := &env.Servers[i]
+			if server.User == "" {
+				server.User = c.SSH.User
+			}
+			if server.Port == 0 {
+				server.Port = c.SSH.Port
+			}
+			if server.KeyFile == "" {
+				server.KeyFile = c.SSH.KeyFile
+			}
+		}
+		c.Environments[name] = env
+	}
+}
+
+// SecretsConfig 加密配置值 (ENC[...]) 使用的密钥
+type SecretsConfig struct {
+	KeyFile       string   `yaml:"key_file,omitempty"`       // 对称密钥文件，默认为 ~/.deploy/secret.key，DEPLOY_SECRET_KEY 环境变量可直接提供密钥
+	AgeRecipients []string `yaml:"age_recipients,omitempty"` // age 公钥，配置后加密使用 age
+	AgeIdentity   string   `yaml:"age_identity,omitempty"`   // age 私钥文件，默认为 ~/.deploy/age.key，可通过 DEPLOY_AGE_IDENTITY 指定
+}
+
+// CacheConfig 依赖缓存目录配置
+type CacheConfig struct {
+	Enabled bool   `yaml:"enabled,omitempty"` // 设置 DEPLOY_CACHE_DIR 环境变量时自动启用
+	Dir     string `yaml:"dir,omitempty"`     // 缓存根目录，默认为 DEPLOY_CACHE_DIR 或 ~/.deploy/cache
+	Scope   string `yaml:"scope,omitempty"`   // global: 所有项目共享 (默认)；project: 每个项目单独缓存
+	NPM     string `yaml:"npm,omitempty"`     // 单独指定 npm 缓存目录 (npm_config_cache)
+	Maven   string `yaml:"maven,omitempty"`   // 单独指定 Maven 本地仓库 (-Dmaven.repo.local)
+	Gradle  string `yaml:"gradle,omitempty"`  // 单独指定 Gradle 用户目录 (GRADLE_USER_HOME)
+	Go      string `yaml:"go,omitempty"`      // 单独指定 Go 模块缓存 (GOMODCACHE)
+	Pip     string `yaml:"pip,omitempty"`     // 单独指定 pip 缓存目录 (PIP_CACHE_DIR)
+}
+
+// LoadConfig 加载配置文件，忽略未知的配置项
 func LoadConfig(configPath string) (*Config, error) {
+	return loadConfig(configPath, false)
+}
+
+// LoadConfigStrict 加载配置文件，存在未知的配置项时返回错误
+func LoadConfigStrict(configPath string) (*Config, error) {
+	return loadConfig(configPath, true)
+}
+
+// loadConfig 读取并解析配置文件，替换 ${VAR} 并写入 DEPLOY_CFG_<配置项> 环境变量
+func loadConfig(configPath string, strict bool) (*Config, error) {
 	if configPath == "" {
 		configPath = "deploy.yaml"
 	}
@@ -119,15 +356,18 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("配置文件不存在: %s", configPath)
 	}
 
-	viper.SetConfigFile(configPath)
-	viper.SetConfigType("yaml")
-
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("读取配置文件失败: %w", err)
-	}
+	return LoadEnvironmentConfig(configPath, "", strict)
+}
 
+// ParseConfig 解析 YAML 配置内容，配置项名称与 yaml 标签一致 (例如 build_command)
+//
+// strict 为 true 时存在未知的配置项会返回错误，错误信息中包含行号。
+func ParseConfig(data []byte, strict bool) (*Config, error) {
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(strict)
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
 		return nil, fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
@@ -137,6 +377,7 @@ func LoadConfig(configPath string) (*Config, error) {
 // GetDefaultConfig 获取默认配置
 func GetDefaultConfig() *Config {
 	return &Config{
+		Version: CurrentVersion,
 		Project: ProjectConfig{
 			Name: "my-app",
 			Type: "auto",
@@ -222,14 +463,17 @@ func SaveConfig(config *Config, configPath string) error {
 		return fmt.Errorf("创建配置目录失败: %w", err)
 	}
 
-	viper.Set("project", config.Project)
-	viper.Set("npm", config.NPM)
-	viper.Set("java", config.Java)
-	viper.Set("scripts", config.Scripts)
-	viper.Set("environments", config.Environments)
-	viper.Set("deploy", config.Deploy)
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return fmt.Errorf("生成配置文件失败: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("生成配置文件失败: %w", err)
+	}
 
-	if err := viper.WriteConfigAs(configPath); err != nil {
+	if err := os.WriteFile(configPath, buf.Bytes(), 0644); err != nil {
 		return fmt.Errorf("保存配置文件失败: %w", err)
 	}
 
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readmeSample 拼接 README 中从"项目配置"到"环境配置"的 YAML 示例，作为一个完整的配置文件
+func readmeSample(t *testing.T) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	readme := string(data)
+	start := strings.Index(readme, "### 项目配置")
+	end := strings.Index(readme, "### 环境覆盖配置")
+	if start < 0 || end < start {
+		t.Fatal("README 中没有找到配置示例")
+	}
+
+	var sample strings.Builder
+	for _, block := range strings.Split(readme[start:end], "```yaml\n")[1:] {
+		sample.WriteString(block[:strings.Index(block, "```")])
+	}
+	return []byte(sample.String())
+}
+
+// readmeSampleConfig README 配置示例对应的配置
+func readmeSampleConfig() *Config {
+	compressionLevel := 6
+	return &Config{
+		Version: 2,
+		Project: ProjectConfig{Name: "my-app", Type: "auto"},
+		NPM: NPMConfig{
+			BuildCommand:        "npm run build",
+			BuildDir:            "dist",
+			InstallCommand:      "npm ci",
+			NodeVersion:         "18",
+			DefaultStartCommand: "pm2 restart ecosystem.config.js",
+			DefaultStopCommand:  "pm2 stop my-app",
+		},
+		Java: JavaConfig{
+			BuildTool:    "maven",
+			BuildCommand: "mvn clean package -DskipTests",
+			ArtifactPath: "target/*.jar",
+			JavaVersion:  "11",
+			Runtime: JavaRuntime{
+				HeapSize: HeapSize{Min: "512m", Max: "2g"},
+				JvmOptions: []string{
+					"-XX:+UseG1GC",
+					"-XX:+HeapDumpOnOutOfMemoryError",
+					"-XX:HeapDumpPath=/opt/app/logs",
+					"-Dfile.encoding=UTF-8",
+					"-Duser.timezone=Asia/Shanghai",
+				},
+				AppOptions: []string{"--server.port=8080", "--spring.profiles.active=prod"},
+			},
+			DefaultStartCommand: "nohup java -Xms{{.HeapMin}} -Xmx{{.HeapMax}} {{.JvmOptions}} -jar {{.JarFile}} {{.AppOptions}} > {{.LogFile}} 2>&1 & echo $! > {{.PidFile}}",
+		},
+		Artifact: ArtifactConfig{
+			Format:           "tar.gz",
+			CompressionLevel: &compressionLevel,
+			Include:          []string{"server.js", "ecosystem.config.js", "package.json"},
+			Exclude:          []string{"**/*.map"},
+			ExtraFiles:       []ExtraFile{{Source: "config/prod.env", Destination: ".env"}},
+			Bundle: BundleConfig{
+				Enabled:             true,
+				Scripts:             []string{"scripts/start.sh"},
+				ConfigFiles:         []string{"src/main/resources/logback.xml"},
+				ApplicationTemplate: "deploy/application.yml.tmpl",
+			},
+			Store: StoreConfig{
+				KeepLast: 10,
+				S3: S3Config{
+					Endpoint: "https://minio.example.com",
+					Region:   "us-east-1",
+					Bucket:   "releases",
+					Prefix:   "deploy",
+					PartSize: 16,
+				},
+			},
+		},
+		Go: GoConfig{
+			Packages:        []string{"./cmd/server"},
+			Platforms:       []string{"linux/amd64", "linux/arm64"},
+			LDFlags:         "-s -w",
+			VersionVariable: "main.version",
+			Tags:            []string{},
+		},
+		Python: PythonConfig{
+			Interpreter:  "python3",
+			Mode:         "bundle",
+			Requirements: "requirements.txt",
+			TargetDir:    "vendor",
+			PipArgs:      []string{"--index-url=https://pypi.example.com/simple"},
+		},
+		Docker: DockerConfig{
+			Dockerfile: "Dockerfile",
+			Image:      "registry.example.com/team/my-app",
+			Tags:       []string{"latest"},
+			BuildArgs:  map[string]string{"HTTP_PROXY": "http://proxy:8080"},
+			Platform:   "linux/amd64",
+		},
+		Cache: CacheConfig{Enabled: true, Scope: "global"},
+		Environments: map[string]EnvironmentConfig{
+			"dev": {
+				Servers:     []ServerConfig{{Host: "dev.example.com", User: "deploy", Port: 22, KeyFile: "~/.ssh/id_rsa"}},
+				DeployPath:  "/opt/app",
+				ServiceName: "my-app",
+				ServicePort: 8080,
+			},
+			"prod": {
+				Servers: []ServerConfig{
+					{Host: "prod1.example.com", User: "deploy", Port: 22},
+					{Host: "prod2.example.com", User: "deploy", Port: 22},
+				},
+				DeployPath:     "/opt/app",
+				ServiceName:    "my-app",
+				ServicePort:    8080,
+				HealthCheckURL: "http://localhost:8080/health",
+			},
+		},
+	}
+}
+
+func TestParseConfigREADMESample(t *testing.T) {
+	// strict 模式下解析，README 中的配置项都应存在
+	cfg, err := ParseConfig(readmeSample(t), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertConfig(t, cfg, readmeSampleConfig())
+}
+
+func TestLoadConfigREADMESample(t *testing.T) {
+	t.Setenv("DEPLOY_USER_CONFIG", "")
+	path := filepath.Join(t.TempDir(), "deploy.yaml")
+	if err := os.WriteFile(path, readmeSample(t), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfigStrict(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertConfig(t, cfg, readmeSampleConfig())
+
+	// 保存后重新加载，配置项名称仍为下划线形式
+	saved := filepath.Join(t.TempDir(), "deploy.yaml")
+	if err := SaveConfig(cfg, saved); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"build_command:", "deploy_path:", "health_check_url:", "key_file:", "compression_level: 6"} {
+		if !strings.Contains(string(content), key) {
+			t.Errorf("保存的配置文件中缺少 %s", key)
+		}
+	}
+	reloaded, err := LoadConfigStrict(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := readmeSampleConfig()
+	expected.Go.Tags = nil // 空列表不会写入配置文件
+	assertConfig(t, reloaded, expected)
+}
+
+func TestParseConfigStrictUnknownKeys(t *testing.T) {
+	data := []byte("project:\n  name: app\nnpm:\n  buildCommand: npm run build\n")
+
+	if _, err := ParseConfig(data, true); err == nil || !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), "buildCommand") {
+		t.Fatalf("strict 模式下应返回包含行号的错误，实际为 %v", err)
+	}
+
+	cfg, err := ParseConfig(data, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Project.Name != "app" || cfg.NPM.BuildCommand != "" {
+		t.Errorf("非 strict 模式下应忽略未知的配置项: %+v", cfg)
+	}
+}
+
+func TestLoadConfigStrictUnknownKeys(t *testing.T) {
+	t.Setenv("DEPLOY_USER_CONFIG", "")
+	path := filepath.Join(t.TempDir(), "deploy.yaml")
+	content := "version: 2\nproject:\n  name: app\nenvironments:\n  prod:\n    deployPath: /opt/app\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadConfigStrict(path)
+	if err == nil || !strings.Contains(err.Error(), path+":6:5") || !strings.Contains(err.Error(), "deployPath") {
+		t.Fatalf("应返回包含文件和行号的错误，实际为 %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Project.Name != "app" || cfg.Environments["prod"].DeployPath != "" {
+		t.Errorf("非 strict 模式下应忽略未知的配置项: %+v", cfg)
+	}
+}
+
+// assertConfig 逐个顶层配置比较，便于定位不一致的配置项
+func assertConfig(t *testing.T, actual, expected *Config) {
+	t.Helper()
+
+	a, e := reflect.ValueOf(actual).Elem(), reflect.ValueOf(expected).Elem()
+	for i := 0; i < a.NumField(); i++ {
+		if !reflect.DeepEqual(a.Field(i).Interface(), e.Field(i).Interface()) {
+			t.Errorf("%s 不一致:\n实际 %+v\n期望 %+v", a.Type().Field(i).Name, a.Field(i).Interface(), e.Field(i).Interface())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileNames 项目配置文件名，同一目录中有多个时使用靠前的
+var ConfigFileNames = []string{"deploy.yaml", "deploy.yml", ".deploy.yaml", "deploy.toml"}
+
+// ErrConfigNotFound 未找到项目配置文件
+var ErrConfigNotFound = errors.New("配置文件不存在")
+
+// FindConfig 从 dir 开始向上级目录查找项目配置文件，找不到时返回 ErrConfigNotFound
+func FindConfig(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("获取目录绝对路径失败: %w", err)
+	}
+
+	for current := absDir; ; {
+		for _, name := range ConfigFileNames {
+			path := filepath.Join(current, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return relativePath(path), nil
+			}
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+
+	return "", fmt.Errorf("%w: 在 %s 及其上级目录中未找到 %s", ErrConfigNotFound, dir, strings.Join(ConfigFileNames, "、"))
+}
+
+// UserConfigPath 用户级配置文件路径，默认为 ~/.config/deploy/config.yaml
+//
+// 可通过 DEPLOY_USER_CONFIG 环境变量指定其他文件，设置为空时不加载用户级配置。
+func UserConfigPath() string {
+	if path, ok := os.LookupEnv("DEPLOY_USER_CONFIG"); ok {
+		return path
+	}
+
+	dir := userConfigDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "config.yaml")
+}
+
+// UserTemplatesDir 用户自定义项目模板目录，默认为 ~/.config/deploy/templates
+func UserTemplatesDir() string {
+	dir := userConfigDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "templates")
+}
+
+// userConfigDir 用户级配置目录，默认为 ~/.config/deploy，遵循 XDG_CONFIG_HOME
+func userConfigDir() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "deploy")
+}
+
+// IsTOML 检查配置文件是否为 TOML 格式
+func IsTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// readDocument 读取配置文件，返回顶层对象节点；TOML 文件转换为等价的 YAML 节点，没有行号
+func readDocument(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	var document yaml.Node
+	if IsTOML(path) {
+		values := make(map[string]interface{})
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("%s: 解析配置文件失败: %w", path, err)
+		}
+		if err := document.Encode(values); err != nil {
+			return nil, fmt.Errorf("%s: 解析配置文件失败: %w", path, err)
+		}
+		return &document, nil
+	}
+
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%s: 解析配置文件失败: %w", path, err)
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if len(document.Content) > 0 && document.Content[0].Kind == yaml.MappingNode {
+		root = document.Content[0]
+	}
+	return root, nil
+}
+
+// relativePath 尽量使用相对于当前目录的路径，便于阅读
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// displayPath 将用户主目录显示为 ~
+func displayPath(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
@@ -0,0 +1,351 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// overridePrefix 覆盖配置项的环境变量前缀，例如 DEPLOY_CFG_NPM_BUILD_COMMAND 对应 npm.build_command
+//
+// 使用单独的前缀，CI 中常见的 DEPLOY_VERSION、DEPLOY_ENV、DEPLOY_TOKEN 等变量不会覆盖配置。
+const overridePrefix = "DEPLOY_CFG_"
+
+// variablePattern 匹配 ${VAR}、${VAR:-默认值}、${VAR-默认值} 以及转义的 $${
+var variablePattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?-)([^}]*))?\}`)
+
+// Variables 配置插值使用的变量，进程环境变量优先，其次为 .env.<环境>、.env 文件
+type Variables struct {
+	Files  []string          // 已加载的 .env 文件
+	values map[string]string // .env 文件中的变量
+}
+
+// LoadVariables 加载 dir 中的 .env 和 .env.<环境> 文件，envName 为空时只加载 .env
+func LoadVariables(dir, envName string) (*Variables, error) {
+	vars := &Variables{values: make(map[string]string)}
+
+	files := []string{filepath.Join(dir, ".env")}
+	if envName != "" {
+		files = append(files, filepath.Join(dir, ".env."+envName))
+	}
+	for _, file := range files {
+		values, err := readDotEnv(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			vars.values[name] = value
+		}
+		vars.Files = append(vars.Files, file)
+	}
+
+	return vars, nil
+}
+
+// Lookup 获取变量值
+func (v *Variables) Lookup(name string) (string, bool) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, true
+	}
+	value, ok := v.values[name]
+	return value, ok
+}
+
+// overrides 获取 DEPLOY_CFG_ 开头的变量，按名称排序
+func (v *Variables) overrides() []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if strings.HasPrefix(name, overridePrefix) && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, entry := range os.Environ() {
+		add(strings.SplitN(entry, "=", 2)[0])
+	}
+	for name := range v.values {
+		add(name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readDotEnv 读取 .env 文件，支持 # 注释、export 前缀以及单双引号
+func readDotEnv(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s:%d: 格式错误，应为 KEY=VALUE", path, number)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			} else {
+				value = value[1 : len(value)-1]
+			}
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			// 未加引号的值中 # 之后为注释
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		values[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// expandVariables 替换标量值中的 ${VAR}，返回未定义的变量
+//
+// ${VAR:-默认值} 在变量未定义或为空时使用默认值，${VAR-默认值} 仅在未定义时使用默认值，
+// $${ 表示字面量 ${。替换后的值按 YAML 规则重新识别类型，因此整数、布尔值配置项也可以使用变量。
+// 命令配置项 (见 isCommandKey) 在运行时由 shell 展开变量，只将 $${ 替换为 ${。
+func expandVariables(node *yaml.Node, path string, vars *Variables) []Issue {
+	var issues []Issue
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			issues = append(issues, expandVariables(child, path, vars)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if isCommandKey(path, key) {
+				unescapeVariables(node.Content[i+1])
+				continue
+			}
+			issues = append(issues, expandVariables(node.Content[i+1], joinPath(path, key), vars)...)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			issues = append(issues, expandVariables(child, fmt.Sprintf("%s[%d]", path, i), vars)...)
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+
+		value := variablePattern.ReplaceAllStringFunc(node.Value, func(match string) string {
+			if match == "$${" {
+				return "${"
+			}
+			groups := variablePattern.FindStringSubmatch(match)
+			name, operator, fallback := groups[1], groups[2], groups[3]
+
+			value, ok := vars.Lookup(name)
+			switch {
+			case operator == ":-" && value == "":
+				return fallback
+			case operator == "-" && !ok:
+				return fallback
+			case !ok:
+				issues = append(issues, Issue{
+					Line:    node.Line,
+					Column:  node.Column,
+					Path:    path,
+					Message: fmt.Sprintf("未定义的环境变量 %s (可使用 ${%s:-默认值} 指定默认值)", name, name),
+				})
+			}
+			return value
+		})
+
+		node.Value = value
+		node.Tag = ""
+		node.Style = 0
+	}
+
+	return issues
+}
+
+// isCommandKey 检查 path 下的 key 是否为命令配置项：*_command、exec_start，
+// 以及 scripts 中的 deploy、variables、custom、hooks
+func isCommandKey(path, key string) bool {
+	if strings.HasSuffix(key, "_command") || key == "exec_start" {
+		return true
+	}
+	parent := path[strings.LastIndex(path, ".")+1:]
+	return parent == "scripts" && (key == "deploy" || key == "variables" || key == "custom" || key == "hooks")
+}
+
+// unescapeVariables 将命令配置项中的 $${ 替换为 ${，其余 ${VAR} 保持原样
+func unescapeVariables(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		node.Value = strings.ReplaceAll(node.Value, "$${", "${")
+		return
+	}
+	for _, child := range node.Content {
+		unescapeVariables(child)
+	}
+}
+
+// applyOverrides 将 DEPLOY_CFG_<配置项> 变量写入配置，层级之间以及名称中的 - 都使用 _ 表示
+//
+// 例如 DEPLOY_CFG_NPM_BUILD_COMMAND 对应 npm.build_command，
+// DEPLOY_CFG_ENVIRONMENTS_PROD_SERVERS_0_HOST 对应 environments.prod.servers[0].host，
+// 字符串列表使用逗号分隔。不对应任何配置项的变量会被忽略。
+func (e *Effective) applyOverrides(vars *Variables) {
+	for _, name := range vars.overrides() {
+		value, _ := vars.Lookup(name)
+		tokens := strings.Split(strings.TrimPrefix(name, overridePrefix), "_")
+		e.override(e.root, reflect.TypeOf(Config{}), tokens, value, "环境变量 "+name)
+	}
+}
+
+// override 按配置结构查找 tokens 对应的配置项并写入 value，找不到时返回 false
+func (e *Effective) override(node *yaml.Node, t reflect.Type, tokens []string, value, origin string) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return false
+		}
+		fields := yamlFields(t)
+		// 字段名本身可能包含 _，优先匹配更长的名称
+		for n := len(tokens); n >= 1; n-- {
+			name := strings.ToLower(strings.Join(tokens[:n], "_"))
+			field, ok := fields[name]
+			if !ok {
+				continue
+			}
+			if e.overrideChild(node, name, field.Type, tokens[n:], value, origin) {
+				return true
+			}
+		}
+
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return false
+		}
+		if t.Elem().Kind() == reflect.String {
+			// 字符串映射 (例如 scripts.variables) 的键为剩余部分，已存在时不区分大小写
+			key := strings.Join(tokens, "_")
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if strings.EqualFold(node.Content[i].Value, key) {
+					key = node.Content[i].Value
+					break
+				}
+			}
+			return e.overrideChild(node, key, t.Elem(), nil, value, origin)
+		}
+		// 其他映射 (例如 environments) 只匹配已存在的键
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			n := len(strings.Split(key, "_"))
+			if n > len(tokens) || !strings.EqualFold(strings.ReplaceAll(key, "-", "_"), strings.Join(tokens[:n], "_")) {
+				continue
+			}
+			if e.override(node.Content[i+1], t.Elem(), tokens[n:], value, origin) {
+				return true
+			}
+		}
+
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode || len(tokens) == 0 {
+			return false
+		}
+		index, err := strconv.Atoi(tokens[0])
+		if err != nil || index < 0 || index >= len(node.Content) {
+			return false
+		}
+		if len(tokens) == 1 {
+			node.Content[index] = e.overrideValue(t.Elem(), value, origin)
+			return true
+		}
+		return e.override(node.Content[index], t.Elem(), tokens[1:], value, origin)
+	}
+
+	return false
+}
+
+// overrideChild 写入对象中的配置项，tokens 为空时替换值，否则继续查找下一级
+func (e *Effective) overrideChild(node *yaml.Node, key string, t reflect.Type, tokens []string, value, origin string) bool {
+	i := mappingIndex(node, key)
+
+	if len(tokens) == 0 {
+		replacement := e.overrideValue(t, value, origin)
+		if i >= 0 {
+			node.Content[i+1] = replacement
+		} else {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, replacement)
+		}
+		return true
+	}
+
+	if i >= 0 {
+		return e.override(node.Content[i+1], t, tokens, value, origin)
+	}
+
+	// 上级配置项不存在时先创建对象，写入失败时不保留
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct && t.Kind() != reflect.Map {
+		return false
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if !e.override(child, t, tokens, value, origin) {
+		return false
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+	return true
+}
+
+// overrideValue 根据配置项类型生成节点，字符串列表使用逗号分隔
+func (e *Effective) overrideValue(t reflect.Type, value, origin string) *yaml.Node {
+	var node *yaml.Node
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String {
+		node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+	} else if t.Kind() == reflect.String {
+		node = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	} else {
+		node = &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	}
+
+	e.track(node, origin)
+	return node
+}
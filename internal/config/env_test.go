@@ -0,0 +1,181 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVariablePrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		process  map[string]string // 进程环境变量
+		dotEnv   string
+		envFile  string // .env.prod
+		expected string
+	}{
+		{name: ".env", dotEnv: "APP_ROOT=/srv/dotenv\n", expected: "/srv/dotenv"},
+		{name: ".env.prod 覆盖 .env", dotEnv: "APP_ROOT=/srv/dotenv\n", envFile: "APP_ROOT=/srv/prod\n", expected: "/srv/prod"},
+		{name: "进程环境变量优先", process: map[string]string{"APP_ROOT": "/srv/process"}, dotEnv: "APP_ROOT=/srv/dotenv\n", envFile: "APP_ROOT=/srv/prod\n", expected: "/srv/process"},
+		{name: "引号和注释", dotEnv: "# 注释\nexport APP_ROOT=\"/srv/quoted\"\n", expected: "/srv/quoted"},
+		{name: "未加引号的值中 # 之后为注释", dotEnv: "APP_ROOT=/srv/plain # 注释\n", expected: "/srv/plain"},
+		{name: "默认值", expected: "/opt/default"},
+		{name: ":- 在值为空时使用默认值", process: map[string]string{"APP_ROOT": ""}, expected: "/opt/default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEPLOY_USER_CONFIG", "")
+			for name, value := range tt.process {
+				t.Setenv(name, value)
+			}
+			dir := t.TempDir()
+			if tt.dotEnv != "" {
+				writeConfigFile(t, filepath.Join(dir, ".env"), tt.dotEnv)
+			}
+			if tt.envFile != "" {
+				writeConfigFile(t, filepath.Join(dir, ".env.prod"), tt.envFile)
+			}
+			path := filepath.Join(dir, "deploy.yaml")
+			writeConfigFile(t, path, "version: 2\nproject:\n  name: app\nenvironments:\n  prod:\n    deploy_path: ${APP_ROOT:-/opt/default}\n")
+
+			cfg, err := LoadEnvironmentConfig(path, "prod", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := cfg.Environments["prod"].DeployPath; actual != tt.expected {
+				t.Errorf("deploy_path 为 %q，应为 %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	tests := []struct {
+		name    string
+		process map[string]string
+		content string
+		check   func(t *testing.T, cfg *Config)
+		err     string // 期望的错误信息，为空时应加载成功
+	}{
+		{
+			name:    "未定义的变量",
+			content: "project:\n  name: app\nenvironments:\n  prod:\n    deploy_path: ${MISSING_ROOT}\n",
+			err:     "deploy.yaml:6:18: environments.prod.deploy_path: 未定义的环境变量 MISSING_ROOT",
+		},
+		{
+			name:    "- 只在未定义时使用默认值",
+			process: map[string]string{"APP_USER": ""},
+			content: "project:\n  name: app-${APP_USER-default}\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Project.Name != "app-" {
+					t.Errorf("project.name 为 %q，应为 app-", cfg.Project.Name)
+				}
+			},
+		},
+		{
+			name:    "替换后按 YAML 规则识别类型",
+			process: map[string]string{"APP_PORT": "8080"},
+			content: "project:\n  name: app\nenvironments:\n  prod:\n    service_port: ${APP_PORT}\n",
+			check: func(t *testing.T, cfg *Config) {
+				if port := cfg.Environments["prod"].ServicePort; port != 8080 {
+					t.Errorf("service_port 为 %d，应为 8080", port)
+				}
+			},
+		},
+		{
+			name:    "类型不匹配",
+			process: map[string]string{"APP_PORT": "http"},
+			content: "project:\n  name: app\nenvironments:\n  prod:\n    service_port: ${APP_PORT}\n",
+			err:     "environments.prod.service_port",
+		},
+		{
+			name:    "$${ 表示字面量",
+			content: "project:\n  name: app\nenvironments:\n  prod:\n    deploy_path: /opt/$${APP_ROOT}\n",
+			check: func(t *testing.T, cfg *Config) {
+				if path := cfg.Environments["prod"].DeployPath; path != "/opt/${APP_ROOT}" {
+					t.Errorf("deploy_path 为 %q，应为 /opt/${APP_ROOT}", path)
+				}
+			},
+		},
+		{
+			name:    "命令配置项交给 shell 展开",
+			content: "project:\n  name: app\nnpm:\n  build_command: npm run build -- --out ${MISSING_ROOT} $${HOME}\nenvironments:\n  prod:\n    scripts:\n      deploy: echo ${MISSING_ROOT}\n      variables:\n        DATA_DIR: ${HOME}/data\n",
+			check: func(t *testing.T, cfg *Config) {
+				if command := cfg.NPM.BuildCommand; command != "npm run build -- --out ${MISSING_ROOT} ${HOME}" {
+					t.Errorf("npm.build_command 为 %q", command)
+				}
+				scripts := cfg.Environments["prod"].Scripts
+				if scripts.Deploy != "echo ${MISSING_ROOT}" || scripts.Variables["DATA_DIR"] != "${HOME}/data" {
+					t.Errorf("scripts 中的变量不应替换: %+v", scripts)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEPLOY_USER_CONFIG", "")
+			for name, value := range tt.process {
+				t.Setenv(name, value)
+			}
+			path := filepath.Join(t.TempDir(), "deploy.yaml")
+			writeConfigFile(t, path, "version: 2\n"+tt.content)
+
+			cfg, err := LoadEnvironmentConfig(path, "", true)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("错误应包含 %q，实际为 %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestInvalidDotEnv(t *testing.T) {
+	t.Setenv("DEPLOY_USER_CONFIG", "")
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, ".env"), "APP_ROOT=/srv\nnot a variable\n")
+	path := filepath.Join(dir, "deploy.yaml")
+	writeConfigFile(t, path, "project:\n  name: app\n")
+
+	if _, err := LoadEnvironmentConfig(path, "", false); err == nil || !strings.Contains(err.Error(), ".env:2: 格式错误") {
+		t.Fatalf("应返回包含行号的错误，实际为 %v", err)
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	t.Setenv("DEPLOY_USER_CONFIG", "")
+	t.Setenv("DEPLOY_CFG_NPM_BUILD_DIR", "out")
+	t.Setenv("DEPLOY_CFG_ENVIRONMENTS_PROD_SERVERS_0_HOST", "10.0.0.5")
+	t.Setenv("DEPLOY_CFG_JAVA_RUNTIME_JVM_OPTIONS", "-XX:+UseG1GC, -Xss512k")
+	t.Setenv("DEPLOY_CFG_UNKNOWN_KEY", "ignored")
+	// CI 中常见的变量不是 DEPLOY_CFG_ 开头，不覆盖配置
+	t.Setenv("DEPLOY_VERSION", "9")
+	t.Setenv("DEPLOY_PROJECT_NAME", "ci")
+
+	path := filepath.Join(t.TempDir(), "deploy.yaml")
+	writeConfigFile(t, path, "version: 2\nproject:\n  name: app\nenvironments:\n  prod:\n    servers:\n      - host: localhost\n")
+
+	cfg, err := LoadEnvironmentConfig(path, "prod", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Version != 2 || cfg.Project.Name != "app" {
+		t.Errorf("DEPLOY_ 开头的其他变量不应覆盖配置: version=%d project.name=%s", cfg.Version, cfg.Project.Name)
+	}
+	if cfg.NPM.BuildDir != "out" {
+		t.Errorf("npm.build_dir 为 %q，应为 out", cfg.NPM.BuildDir)
+	}
+	if host := cfg.Environments["prod"].Servers[0].Host; host != "10.0.0.5" {
+		t.Errorf("environments.prod.servers[0].host 为 %q，应为 10.0.0.5", host)
+	}
+	if options := cfg.Java.Runtime.JvmOptions; len(options) != 2 || options[0] != "-XX:+UseG1GC" || options[1] != "-Xss512k" {
+		t.Errorf("java.runtime.jvm_options 为 %q", options)
+	}
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion 当前的配置文件版本，未指定 version 的配置文件视为版本 1
+const CurrentVersion = 2
+
+// Migration 将配置文件从 From 版本升级到 From+1
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(root *yaml.Node) []string // 修改顶层对象节点，返回修改内容
+}
+
+// migrations 按版本排列的升级步骤，修改配置结构时在末尾添加并增加 CurrentVersion
+var migrations = []Migration{
+	{
+		From:        1,
+		Description: "配置项名称改为下划线形式 (例如 buildCommand 改为 build_command)，非命令配置项中的 ${VAR} 在加载时替换",
+		Apply:       migrateKeyNames,
+	},
+}
+
+// MigrationResult 配置文件升级结果
+type MigrationResult struct {
+	From    int
+	To      int
+	Changes []string
+}
+
+// Migrated 检查配置文件是否需要升级
+func (r *MigrationResult) Migrated() bool {
+	return r.From != r.To
+}
+
+// MigrateFile 升级 YAML 配置文件，返回升级后的内容，保留注释和配置项顺序
+func MigrateFile(path string) (*MigrationResult, []byte, error) {
+	if IsTOML(path) {
+		return nil, nil, fmt.Errorf("仅支持升级 YAML 配置文件: %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, fmt.Errorf("%s: 解析配置文件失败: %w", path, err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("%s: 配置文件为空", path)
+	}
+
+	result, err := migrate(document.Content[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if !result.Migrated() {
+		return result, data, nil
+	}
+
+	content, err := encodeDocument(&document)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, content, nil
+}
+
+// migrate 依次执行升级步骤并写入 version，版本高于 CurrentVersion 时返回错误
+func migrate(root *yaml.Node) (*MigrationResult, error) {
+	version, err := fileVersion(root)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("配置文件版本 %d 高于当前支持的版本 %d，请升级 deploy", version, CurrentVersion)
+	}
+
+	result := &MigrationResult{From: version, To: version}
+	for _, migration := range migrations {
+		if migration.From < result.To {
+			continue
+		}
+		result.Changes = append(result.Changes, migration.Apply(root)...)
+		result.To = migration.From + 1
+	}
+	if result.Migrated() {
+		setVersion(root, result.To)
+	}
+	return result, nil
+}
+
+// fileVersion 获取配置文件的 version，未指定时为 1
+func fileVersion(root *yaml.Node) (int, error) {
+	node := mappingValue(root, "version")
+	if node == nil {
+		return 1, nil
+	}
+
+	version, err := strconv.Atoi(node.Value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("第 %d 行: version 应为正整数: %s", node.Line, node.Value)
+	}
+	return version, nil
+}
+
+// setVersion 写入 version，不存在时添加到最前面，原有的文件开头注释保留在最前面
+func setVersion(root *yaml.Node, version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if i := mappingIndex(root, "version"); i >= 0 {
+		value.LineComment = root.Content[i+1].LineComment
+		root.Content[i+1] = value
+		return
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	if len(root.Content) > 0 {
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// migrateKeyNames 版本 1 → 2：将 viper 读取的不区分大小写的字段名 (buildCommand、deploypath)
+// 改为下划线形式的配置项名称 (build_command、deploy_path)，并列出加载时会替换 ${VAR} 的配置项
+func migrateKeyNames(root *yaml.Node) []string {
+	var changes []string
+	renameKeys(root, reflect.TypeOf(Config{}), "", &changes)
+	noteVariables(root, "", &changes)
+	return changes
+}
+
+// noteVariables 版本 1 原样保留 ${VAR}，版本 2 在加载时替换非命令配置项中的变量，
+// 不修改配置文件，只列出受影响的配置项
+func noteVariables(node *yaml.Node, path string, changes *[]string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if key := node.Content[i].Value; !isCommandKey(path, key) {
+				noteVariables(node.Content[i+1], joinPath(path, key), changes)
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			noteVariables(child, fmt.Sprintf("%s[%d]", path, i), changes)
+		}
+	case yaml.ScalarNode:
+		if strings.Contains(strings.ReplaceAll(node.Value, "$${", ""), "${") {
+			*changes = append(*changes, fmt.Sprintf("%s: 其中的 ${VAR} 将在加载时替换为环境变量，需要保留原样时写成 $${VAR}", path))
+		}
+	}
+}
+
+// renameKeys 按配置结构将与字段名相同 (不区分大小写) 的未知配置项改为 yaml 名称
+func renameKeys(node *yaml.Node, t reflect.Type, path string, changes *[]string) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			field, ok := fields[key.Value]
+			if !ok {
+				field, ok = fieldByName(t, key.Value)
+				if !ok {
+					continue
+				}
+				name := yamlName(field)
+				if mappingIndex(node, name) >= 0 {
+					*changes = append(*changes, fmt.Sprintf("%s: 已存在 %s，未修改", joinPath(path, key.Value), name))
+					continue
+				}
+				*changes = append(*changes, fmt.Sprintf("%s → %s", joinPath(path, key.Value), joinPath(path, name)))
+				key.Value = name
+			}
+			renameKeys(node.Content[i+1], field.Type, joinPath(path, key.Value), changes)
+		}
+
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			renameKeys(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value), changes)
+		}
+
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, child := range node.Content {
+			renameKeys(child, t.Elem(), fmt.Sprintf("%s[%d]", path, i), changes)
+		}
+	}
+}
+
+// fieldByName 按 Go 字段名查找字段，不区分大小写
+func fieldByName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if yamlName(field) != "" && strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
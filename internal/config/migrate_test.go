@@ -0,0 +1,152 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		from    int
+		changes []string
+		check   func(t *testing.T, cfg *Config)
+		err     string
+	}{
+		{
+			name:    "字段名改为下划线形式",
+			content: "project:\n  name: app\nnpm:\n  buildCommand: npm run build\n  BuildDir: out\nenvironments:\n  prod:\n    deploypath: /opt/app\n    healthCheckURL: http://localhost/health\n",
+			from:    1,
+			changes: []string{
+				"npm.buildCommand → npm.build_command",
+				"npm.BuildDir → npm.build_dir",
+				"environments.prod.deploypath → environments.prod.deploy_path",
+				"environments.prod.healthCheckURL → environments.prod.health_check_url",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.NPM.BuildCommand != "npm run build" || cfg.NPM.BuildDir != "out" {
+					t.Errorf("npm 配置未升级: %+v", cfg.NPM)
+				}
+				if env := cfg.Environments["prod"]; env.DeployPath != "/opt/app" || env.HealthCheckURL != "http://localhost/health" {
+					t.Errorf("环境配置未升级: %+v", env)
+				}
+			},
+		},
+		{
+			name:    "已存在下划线形式的配置项时不修改",
+			content: "npm:\n  build_dir: dist\n  buildDir: out\n",
+			from:    1,
+			changes: []string{"npm.buildDir: 已存在 build_dir，未修改"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.NPM.BuildDir != "dist" {
+					t.Errorf("npm.build_dir 为 %q，应保持为 dist", cfg.NPM.BuildDir)
+				}
+			},
+		},
+		{
+			name:    "列表和映射中的字段名",
+			content: "environments:\n  prod:\n    servers:\n      - host: a\n        keyFile: ~/.ssh/id_rsa\n",
+			from:    1,
+			changes: []string{"environments.prod.servers[0].keyFile → environments.prod.servers[0].key_file"},
+			check: func(t *testing.T, cfg *Config) {
+				if server := cfg.Environments["prod"].Servers[0]; server.KeyFile != "~/.ssh/id_rsa" {
+					t.Errorf("servers[0].key_file 为 %q", server.KeyFile)
+				}
+			},
+		},
+		{
+			name:    "列出加载时会替换 ${VAR} 的配置项",
+			content: "project:\n  name: app\nnpm:\n  buildCommand: echo ${HOME}\nenvironments:\n  prod:\n    deploy_path: ${APP_ROOT}\n    static:\n      web_root: /srv/$${literal}\n",
+			from:    1,
+			changes: []string{
+				"npm.buildCommand → npm.build_command",
+				"environments.prod.deploy_path: 其中的 ${VAR} 将在加载时替换为环境变量，需要保留原样时写成 $${VAR}",
+			},
+		},
+		{
+			name:    "当前版本不修改",
+			content: "version: 2\nnpm:\n  buildCommand: npm run build\n",
+			from:    2,
+		},
+		{
+			name:    "版本高于当前版本",
+			content: "version: 3\n",
+			err:     "配置文件版本 3 高于当前支持的版本 2",
+		},
+		{
+			name:    "版本格式错误",
+			content: "version: two\n",
+			err:     "第 1 行: version 应为正整数: two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var document yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.content), &document); err != nil {
+				t.Fatal(err)
+			}
+			root := document.Content[0]
+
+			result, err := migrate(root)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("错误应包含 %q，实际为 %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.From != tt.from || result.To != CurrentVersion {
+				t.Errorf("版本为 %d → %d，应为 %d → %d", result.From, result.To, tt.from, CurrentVersion)
+			}
+			if !reflect.DeepEqual(result.Changes, tt.changes) {
+				t.Errorf("修改内容为 %q，应为 %q", result.Changes, tt.changes)
+			}
+			if version := mappingValue(root, "version"); version == nil || version.Value != "2" {
+				t.Errorf("升级后 version 应为 2")
+			}
+
+			if tt.check != nil {
+				var cfg Config
+				if err := root.Decode(&cfg); err != nil {
+					t.Fatal(err)
+				}
+				tt.check(t, &cfg)
+			}
+		})
+	}
+}
+
+func TestMigrateFileKeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deploy.yaml")
+	writeConfigFile(t, path, "# 项目配置\nproject:\n  name: app # 项目名称\nnpm:\n  buildCommand: npm run build\n")
+
+	result, content, err := MigrateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Migrated() {
+		t.Fatal("版本 1 的配置文件应升级")
+	}
+	expected := "# 项目配置\nversion: 2\nproject:\n  name: app # 项目名称\nnpm:\n  build_command: npm run build\n"
+	if string(content) != expected {
+		t.Errorf("升级后的内容为:\n%s\n应为:\n%s", content, expected)
+	}
+
+	// 已是当前版本时原样返回
+	writeConfigFile(t, path, expected)
+	result, content, err = MigrateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Migrated() || string(content) != expected {
+		t.Errorf("当前版本的配置文件不应修改:\n%s", content)
+	}
+}
@@ -0,0 +1,364 @@
+package config
+
+import (
+	"bytes"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// overlaySections 可以在 environments.<环境> 中覆盖的顶层配置
+//
+// scripts 中只有 global、custom、hooks 会覆盖顶层配置，deploy 和 variables 属于环境本身。
+var overlaySections = []string{"npm", "java", "go", "python", "scripts", "deploy", "artifact", "docker", "cache", "publish", "secrets"}
+
+// overlayScripts environments.<环境>.scripts 中覆盖顶层 scripts 的配置项
+var overlayScripts = []string{"global", "custom", "hooks"}
+
+// appendTag 标记追加到原列表之后的列表，未标记的列表整体替换
+const appendTag = "!append"
+
+// Effective 合并环境覆盖配置后的有效配置
+//
+// 合并顺序（后者优先）：
+//  1. 用户级配置 (~/.config/deploy/config.yaml) 中的顶层配置
+//  2. deploy.yaml 中的顶层配置
+//  3. 用户级配置和 deploy.yaml 中 environments.<环境> 下的 npm、java、scripts、deploy 等配置
+//  4. deploy.<环境>.yaml 中的顶层配置
+//  5. deploy.<环境>.yaml 中 environments.<环境> 下的覆盖配置
+//
+// 对象逐项深度合并，标量和列表整体替换，带 !append 标签的列表追加到原列表之后，
+// 值为 null (~) 时恢复为默认值。
+type Effective struct {
+	Environment string   // 环境名称，为空时不合并覆盖配置
+	Files       []string // 参与合并的配置文件
+	EnvFiles    []string // 提供变量的 .env 文件
+
+	dir     string // 配置文件所在目录
+	root    *yaml.Node
+	origins map[*yaml.Node]string // 节点来自的配置文件
+}
+
+// LoadEnvironmentConfig 加载配置文件并合并指定环境的覆盖配置
+func LoadEnvironmentConfig(configPath, envName string, strict bool) (*Config, error) {
+	effective, err := ResolveEnvironment(configPath, envName, strict)
+	if err != nil {
+		return nil, err
+	}
+	return effective.Config()
+}
+
+// OverlayPath 环境覆盖配置文件的路径，例如 deploy.yaml 对应 deploy.prod.yaml
+func OverlayPath(configPath, envName string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "." + envName + ext
+}
+
+// ResolveEnvironment 读取配置文件和环境覆盖配置文件并合并，strict 为 true 时各文件中不能有未知的配置项
+//
+// 合并前替换各文件中的 ${VAR}，变量来自环境变量以及配置文件目录中的 .env、.env.<环境>；
+// 合并后写入 DEPLOY_CFG_<配置项> 环境变量，最后解密顶层和当前环境中的 ENC[...] 值。
+func ResolveEnvironment(configPath, envName string, strict bool) (*Effective, error) {
+	e, err := resolveLayers(configPath, envName, strict)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.decryptSecrets(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// resolveLayers 合并各配置文件并写入环境变量覆盖，不解密
+func resolveLayers(configPath, envName string, strict bool) (*Effective, error) {
+	if configPath == "" {
+		configPath = "deploy.yaml"
+	}
+
+	vars, err := LoadVariables(filepath.Dir(configPath), envName)
+	if err != nil {
+		return nil, err
+	}
+	e := &Effective{
+		Environment: envName,
+		EnvFiles:    vars.Files,
+		dir:         filepath.Dir(configPath),
+		origins:     make(map[*yaml.Node]string),
+	}
+
+	// 用户级配置作为最底层，提供 SSH 密钥、缓存目录等个人默认值
+	var layers []*yaml.Node
+	if userPath := UserConfigPath(); userPath != "" && utils.FileExists(userPath) {
+		layer, err := e.readLayer(userPath, displayPath(userPath), vars, strict)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+	root, err := e.readLayer(configPath, filepath.Base(configPath), vars, strict)
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, root)
+
+	e.root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, layer := range layers {
+		e.root = e.merge(e.root, e.clone(layer))
+	}
+
+	if envName != "" {
+		for _, layer := range layers {
+			e.applyEnvironment(layer)
+		}
+
+		overlayPath := OverlayPath(configPath, envName)
+		if _, err := os.Stat(overlayPath); err == nil {
+			layer, err := e.readLayer(overlayPath, filepath.Base(overlayPath), vars, strict)
+			if err != nil {
+				return nil, err
+			}
+			e.root = e.merge(e.root, e.clone(layer))
+			e.applyEnvironment(layer)
+		}
+
+		if mappingValue(mappingValue(e.root, "environments"), envName) == nil {
+			return nil, fmt.Errorf("环境不存在: %s", envName)
+		}
+	}
+
+	e.applyOverrides(vars)
+	return e, nil
+}
+
+// Config 解码有效配置，服务器未配置的连接参数使用 ssh 中的默认值
+func (e *Effective) Config() (*Config, error) {
+	root := e.clone(e.root)
+	normalizeTags(root)
+
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, fmt.Errorf("生成有效配置失败: %w", err)
+	}
+	cfg, err := ParseConfig(data, false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.applySSHDefaults()
+	return cfg, nil
+}
+
+// Render 输出有效配置，每个值后以注释标明来源 (文件:行号)；只保留当前环境
+func (e *Effective) Render() ([]byte, error) {
+	root := e.clone(e.root)
+	normalizeTags(root)
+
+	if e.Environment != "" {
+		if environments := mappingValue(root, "environments"); environments != nil {
+			for i := 0; i+1 < len(environments.Content); i += 2 {
+				if environments.Content[i].Value == e.Environment {
+					environments.Content = environments.Content[i : i+2]
+					break
+				}
+			}
+		}
+	}
+	e.annotate(root)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, fmt.Errorf("生成有效配置失败: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("生成有效配置失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// readLayer 读取配置文件并替换变量，返回顶层对象节点；name 为来源注释中显示的文件名
+func (e *Effective) readLayer(path, name string, vars *Variables, strict bool) (*yaml.Node, error) {
+	root, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 旧版本的配置文件先在内存中升级，提示使用 deploy config migrate 更新文件；
+	// 用户级配置通常手写且不含 version，升级没有修改任何配置项时按当前版本处理，不提示
+	result, err := migrate(root)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if result.Migrated() && (path != UserConfigPath() || len(result.Changes) > 0) {
+		utils.PrintWarning(fmt.Sprintf("%s: 配置文件版本 %d 已过时 (当前为 %d)，运行 deploy config migrate 升级", path, result.From, result.To))
+	}
+
+	// 替换变量后按配置结构检查，错误的行号对应原文件
+	issues := expandVariables(root, "", vars)
+	issues = append(issues, checkStructure(root, strict)...)
+	if len(issues) > 0 {
+		messages := make([]string, len(issues))
+		for i, issue := range issues {
+			messages[i] = fmt.Sprintf("%s:%s", path, issue)
+		}
+		return nil, fmt.Errorf("解析配置文件失败:\n  %s", strings.Join(messages, "\n  "))
+	}
+
+	e.Files = append(e.Files, path)
+	e.track(root, name)
+	return root, nil
+}
+
+// applyEnvironment 将配置文件中 environments.<环境> 下的覆盖配置合并到顶层配置
+func (e *Effective) applyEnvironment(layer *yaml.Node) {
+	env := mappingValue(mappingValue(layer, "environments"), e.Environment)
+	if env == nil {
+		return
+	}
+
+	for _, section := range overlaySections {
+		value := mappingValue(env, section)
+		if value == nil {
+			continue
+		}
+		if section == "scripts" {
+			if value = subset(value, overlayScripts); value == nil {
+				continue
+			}
+		}
+
+		overlay := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		overlay.Content = []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: section}, value}
+		e.root = e.merge(e.root, e.clone(overlay))
+	}
+}
+
+// merge 将 overlay 深度合并到 base，返回合并后的节点
+func (e *Effective) merge(base, overlay *yaml.Node) *yaml.Node {
+	switch {
+	case base.Kind == yaml.MappingNode && overlay.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(overlay.Content); i += 2 {
+			key, value := overlay.Content[i], overlay.Content[i+1]
+			if j := mappingIndex(base, key.Value); j >= 0 {
+				base.Content[j+1] = e.merge(base.Content[j+1], value)
+			} else {
+				base.Content = append(base.Content, key, value)
+			}
+		}
+		return base
+
+	case base.Kind == yaml.SequenceNode && overlay.Kind == yaml.SequenceNode && overlay.Tag == appendTag:
+		base.Content = append(base.Content, overlay.Content...)
+		return base
+	}
+
+	return overlay
+}
+
+// track 记录节点来自的配置文件
+func (e *Effective) track(node *yaml.Node, file string) {
+	e.origins[node] = file
+	for _, child := range node.Content {
+		e.track(child, file)
+	}
+}
+
+// clone 深拷贝节点，同时复制来源；别名展开为引用的节点，合并时不会修改锚点
+func (e *Effective) clone(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.AliasNode {
+		return e.clone(node.Alias)
+	}
+
+	copied := *node
+	copied.Anchor = ""
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = e.clone(child)
+	}
+	if origin, ok := e.origins[node]; ok {
+		e.origins[&copied] = origin
+	}
+	return &copied
+}
+
+// annotate 清除原有注释，在每个值后添加来源注释
+func (e *Effective) annotate(node *yaml.Node) {
+	node.HeadComment, node.LineComment, node.FootComment = "", "", ""
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		node.Style = 0
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			node.Content[i].HeadComment, node.Content[i].LineComment, node.Content[i].FootComment = "", "", ""
+			e.annotate(node.Content[i+1])
+		}
+		if len(node.Content) > 0 {
+			return
+		}
+	case yaml.SequenceNode:
+		node.Style = 0
+		for _, child := range node.Content {
+			e.annotate(child)
+		}
+		if len(node.Content) > 0 {
+			return
+		}
+	}
+
+	if _, ok := e.origins[node]; ok {
+		node.LineComment = e.location(node)
+	}
+}
+
+// normalizeTags 将 !append 标签还原为普通列表
+func normalizeTags(node *yaml.Node) {
+	if node.Tag == appendTag {
+		node.Tag = "!!seq"
+	}
+	for _, child := range node.Content {
+		normalizeTags(child)
+	}
+}
+
+// mappingIndex 查找对象中键的位置，不存在时返回 -1
+func mappingIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue 获取对象中键对应的值，不存在时返回 nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	i := mappingIndex(node, key)
+	if i < 0 {
+		return nil
+	}
+	return node.Content[i+1]
+}
+
+// subset 返回只包含指定键的对象，没有匹配的键时返回 nil
+func subset(node *yaml.Node, keys []string) *yaml.Node {
+	result := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range keys {
+		if i := mappingIndex(node, key); i >= 0 {
+			result.Content = append(result.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	if len(result.Content) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// overlayBase 合并测试使用的 deploy.yaml
+const overlayBase = `version: 2
+project:
+  name: app
+npm:
+  build_dir: out
+artifact:
+  exclude: ["*.map"]
+java:
+  runtime:
+    jvm_options: ["-Xss512k", "-XX:+UseG1GC"]
+environments:
+  prod:
+    deploy_path: /opt/app
+`
+
+func TestResolveEnvironmentMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     string // 用户级配置
+		base     string // deploy.yaml 中 environments.prod 之后追加的内容
+		overlay  string // deploy.prod.yaml
+		jvm      []string
+		exclude  []string
+		buildDir string
+	}{
+		{
+			name:     "未覆盖",
+			jvm:      []string{"-Xss512k", "-XX:+UseG1GC"},
+			exclude:  []string{"*.map"},
+			buildDir: "out",
+		},
+		{
+			name:     "列表整体替换",
+			base:     "    java:\n      runtime:\n        jvm_options: [\"-Xmx2g\"]\n",
+			jvm:      []string{"-Xmx2g"},
+			exclude:  []string{"*.map"},
+			buildDir: "out",
+		},
+		{
+			name:     "!append 追加到原列表之后",
+			base:     "    java:\n      runtime:\n        jvm_options: !append [\"-Xmx2g\"]\n",
+			jvm:      []string{"-Xss512k", "-XX:+UseG1GC", "-Xmx2g"},
+			exclude:  []string{"*.map"},
+			buildDir: "out",
+		},
+		{
+			name:     "deploy.prod.yaml 顶层 !append",
+			overlay:  "artifact:\n  exclude: !append [\"*.log\"]\n",
+			jvm:      []string{"-Xss512k", "-XX:+UseG1GC"},
+			exclude:  []string{"*.map", "*.log"},
+			buildDir: "out",
+		},
+		{
+			name:     "deploy.prod.yaml 环境配置在 deploy.yaml 环境配置之后追加",
+			base:     "    java:\n      runtime:\n        jvm_options: !append [\"-Xmx2g\"]\n",
+			overlay:  "environments:\n  prod:\n    java:\n      runtime:\n        jvm_options: !append [\"-Dprod\"]\n",
+			jvm:      []string{"-Xss512k", "-XX:+UseG1GC", "-Xmx2g", "-Dprod"},
+			exclude:  []string{"*.map"},
+			buildDir: "out",
+		},
+		{
+			name:     "项目配置替换用户级配置的列表后再追加",
+			user:     "artifact:\n  exclude: [\"*.tmp\"]\n",
+			overlay:  "artifact:\n  exclude: !append [\"*.log\"]\n",
+			jvm:      []string{"-Xss512k", "-XX:+UseG1GC"},
+			exclude:  []string{"*.map", "*.log"},
+			buildDir: "out",
+		},
+		{
+			name:     "null 恢复为默认值",
+			overlay:  "npm:\n  build_dir: ~\n",
+			jvm:      []string{"-Xss512k", "-XX:+UseG1GC"},
+			exclude:  []string{"*.map"},
+			buildDir: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "deploy.yaml")
+			writeConfigFile(t, path, overlayBase+tt.base)
+			if tt.overlay != "" {
+				writeConfigFile(t, OverlayPath(path, "prod"), "version: 2\n"+tt.overlay)
+			}
+			userPath := filepath.Join(dir, "user.yaml")
+			if tt.user != "" {
+				writeConfigFile(t, userPath, tt.user)
+			}
+			t.Setenv("DEPLOY_USER_CONFIG", userPath)
+
+			cfg, err := LoadEnvironmentConfig(path, "prod", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg.Java.Runtime.JvmOptions, tt.jvm) {
+				t.Errorf("java.runtime.jvm_options 为 %q，应为 %q", cfg.Java.Runtime.JvmOptions, tt.jvm)
+			}
+			if !reflect.DeepEqual(cfg.Artifact.Exclude, tt.exclude) {
+				t.Errorf("artifact.exclude 为 %q，应为 %q", cfg.Artifact.Exclude, tt.exclude)
+			}
+			if cfg.NPM.BuildDir != tt.buildDir {
+				t.Errorf("npm.build_dir 为 %q，应为 %q", cfg.NPM.BuildDir, tt.buildDir)
+			}
+		})
+	}
+}
+
+func TestResolveEnvironmentUnknownEnvironment(t *testing.T) {
+	t.Setenv("DEPLOY_USER_CONFIG", "")
+	path := filepath.Join(t.TempDir(), "deploy.yaml")
+	writeConfigFile(t, path, overlayBase)
+
+	if _, err := LoadEnvironmentConfig(path, "staging", false); err == nil || !strings.Contains(err.Error(), "环境不存在: staging") {
+		t.Fatalf("应返回环境不存在的错误，实际为 %v", err)
+	}
+}
+
+// writeConfigFile 写入测试使用的配置文件
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
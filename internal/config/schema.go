@@ -0,0 +1,90 @@
+package config
+
+import (
+	"deploy/internal/utils"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// JSONSchema 根据配置结构生成 deploy.yaml 的 JSON Schema (draft-07)，供编辑器提供补全和校验
+//
+// 枚举值、端口范围和堆内存格式与 deploy config validate 使用相同的规则。
+func JSONSchema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(Config{}), "")
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "deploy.yaml"
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成 JSON Schema 失败: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaFor 生成类型对应的 JSON Schema，pattern 为匹配 enumValues 和 portFields 的路径
+func schemaFor(t reflect.Type, pattern string) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), pattern)
+
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for name, field := range yamlFields(t) {
+			properties[name] = schemaFor(field.Type, joinPath(pattern, name))
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaFor(t.Elem(), joinPath(pattern, "*")),
+		}
+
+	case reflect.Slice:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaFor(t.Elem(), pattern+"[]"),
+		}
+
+	case reflect.String:
+		if values := schemaEnum(pattern); values != nil {
+			return map[string]interface{}{"type": "string", "enum": values}
+		}
+		if strings.HasSuffix(pattern, "runtime.heap_size.min") || strings.HasSuffix(pattern, "runtime.heap_size.max") {
+			return map[string]interface{}{"type": "string", "pattern": heapSizePattern.String()}
+		}
+		// 版本号、实例数等配置项常写成数字
+		return map[string]interface{}{"type": []string{"string", "number"}}
+
+	case reflect.Int:
+		schema := map[string]interface{}{"type": "integer"}
+		if pattern == "version" {
+			schema["minimum"] = 1
+			schema["maximum"] = CurrentVersion
+		}
+		if portFields[rulePath(pattern)] {
+			schema["minimum"] = 1
+			schema["maximum"] = 65535
+		}
+		return schema
+
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	}
+
+	return map[string]interface{}{}
+}
+
+// schemaEnum 获取配置项的可选值
+func schemaEnum(pattern string) []string {
+	if pattern == "project.type" {
+		return utils.ProjectTypes
+	}
+	return enumValues[rulePath(pattern)]
+}
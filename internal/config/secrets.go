@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"deploy/internal/secrets"
+	"deploy/internal/utils"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyringOptions 获取密钥配置，相对路径相对于配置文件所在目录
+func (s *SecretsConfig) KeyringOptions(dir string) secrets.Options {
+	options := secrets.Options{
+		KeyFile:       s.KeyFile,
+		AgeRecipients: s.AgeRecipients,
+		AgeIdentity:   s.AgeIdentity,
+	}
+	for _, path := range []*string{&options.KeyFile, &options.AgeIdentity} {
+		if *path != "" && !strings.HasPrefix(*path, "~/") && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+	return options
+}
+
+// LoadKeyring 根据配置文件 (及指定环境的覆盖配置) 中的 secrets 配置创建 Keyring
+func LoadKeyring(configPath, envName string) (*secrets.Keyring, error) {
+	e, err := resolveLayers(configPath, envName, false)
+	if err != nil {
+		return nil, err
+	}
+	return e.keyring()
+}
+
+// keyring 根据有效配置中的 secrets 配置创建 Keyring
+func (e *Effective) keyring() (*secrets.Keyring, error) {
+	var cfg SecretsConfig
+	if node := mappingValue(e.root, "secrets"); node != nil {
+		if err := node.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("解析 secrets 配置失败: %w", err)
+		}
+	}
+	return secrets.New(cfg.KeyringOptions(e.dir)), nil
+}
+
+// decryptSecrets 解密顶层和当前环境中的 ENC[...] 值，并登记为敏感值以便在输出中隐藏
+//
+// 其他环境中的值保持加密，没有其他环境密钥的用户也可以部署当前环境。
+func (e *Effective) decryptSecrets() error {
+	keyring, err := e.keyring()
+	if err != nil {
+		return err
+	}
+	return e.decryptNode(e.root, "", keyring)
+}
+
+// shortSecretLength 敏感值少于该长度时给出警告，过短的值容易猜测，替换时也会误伤普通输出
+const shortSecretLength = 4
+
+// decryptNode 解密节点中的加密值
+func (e *Effective) decryptNode(node *yaml.Node, path string, keyring *secrets.Keyring) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			childPath := joinPath(path, key)
+			if path == "environments" && key != e.Environment {
+				continue
+			}
+			if err := e.decryptNode(node.Content[i+1], childPath, keyring); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := e.decryptNode(child, fmt.Sprintf("%s[%d]", path, i), keyring); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if !secrets.IsEncrypted(node.Value) {
+			return nil
+		}
+		plaintext, err := keyring.Decrypt(node.Value)
+		if err != nil {
+			return fmt.Errorf("%s: 解密 %s 失败: %w", e.location(node), path, err)
+		}
+		if len(plaintext) < shortSecretLength {
+			utils.PrintWarning(fmt.Sprintf("%s: %s 解密后少于 %d 个字符，输出中所有相同的文本都会显示为 ******，建议使用更长的值", e.location(node), path, shortSecretLength))
+		}
+		utils.RegisterSecret(plaintext)
+		node.Value = plaintext
+		node.Tag = "!!str"
+		node.Style = 0
+	}
+	return nil
+}
+
+// location 节点的来源，例如 deploy.yaml:12
+func (e *Effective) location(node *yaml.Node) string {
+	origin := e.origins[node]
+	if node.Line > 0 {
+		origin = fmt.Sprintf("%s:%d", origin, node.Line)
+	}
+	return origin
+}
+
+// SecretTag 编辑配置文件时标记明文敏感值的 YAML 标签，保存时重新加密
+const SecretTag = "!secret"
+
+// SecretDocument 解密后供编辑的配置文件
+type SecretDocument struct {
+	Skipped   []string // 无法解密而保持加密的配置项
+	keyring   *secrets.Keyring
+	originals map[string]sealedValue
+}
+
+// sealedValue 编辑前的明文和密文，明文未修改时保留原密文
+type sealedValue struct {
+	plaintext  string
+	ciphertext string
+}
+
+// UnsealSecrets 解密配置文件中的 ENC[...] 值并标记为 !secret，无法解密的值保持加密
+func UnsealSecrets(data []byte, keyring *secrets.Keyring) ([]byte, *SecretDocument, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	d := &SecretDocument{keyring: keyring, originals: make(map[string]sealedValue)}
+	walkScalars(&document, "", func(node *yaml.Node, path string) error {
+		if !secrets.IsEncrypted(node.Value) {
+			return nil
+		}
+		plaintext, err := keyring.Decrypt(node.Value)
+		if err != nil {
+			d.Skipped = append(d.Skipped, fmt.Sprintf("%s (第 %d 行): %v", path, node.Line, err))
+			return nil
+		}
+		d.originals[path] = sealedValue{plaintext: plaintext, ciphertext: node.Value}
+		node.Value = plaintext
+		node.Tag = SecretTag
+		node.Style = 0
+		return nil
+	})
+
+	content, err := encodeDocument(&document)
+	return content, d, err
+}
+
+// Seal 加密编辑后配置文件中标记为 !secret 的值，返回新加密的值的数量
+func (d *SecretDocument) Seal(data []byte) ([]byte, int, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, 0, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	changed := 0
+	err := walkScalars(&document, "", func(node *yaml.Node, path string) error {
+		if node.Tag != SecretTag {
+			return nil
+		}
+		ciphertext := d.originals[path].ciphertext
+		if original, ok := d.originals[path]; !ok || original.plaintext != node.Value {
+			encrypted, err := d.keyring.Encrypt(node.Value)
+			if err != nil {
+				return fmt.Errorf("加密 %s 失败: %w", path, err)
+			}
+			ciphertext = encrypted
+			changed++
+		}
+		node.Value = ciphertext
+		node.Tag = "!!str"
+		node.Style = 0
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	content, err := encodeDocument(&document)
+	return content, changed, err
+}
+
+// walkScalars 遍历所有标量值
+func walkScalars(node *yaml.Node, path string, fn func(node *yaml.Node, path string) error) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := walkScalars(child, path, fn); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := walkScalars(node.Content[i+1], joinPath(path, node.Content[i].Value), fn); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := walkScalars(child, fmt.Sprintf("%s[%d]", path, i), fn); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		return fn(node, path)
+	}
+	return nil
+}
+
+// encodeDocument 以两个空格缩进输出 YAML 文档，与 SaveConfig 一致
+func encodeDocument(document *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, fmt.Errorf("生成配置文件失败: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("生成配置文件失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
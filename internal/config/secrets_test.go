@@ -0,0 +1,143 @@
+package config
+
+import (
+	"deploy/internal/secrets"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// testKeyrings 分别创建使用 AES-GCM 对称密钥和 age 的 Keyring，同时返回解密使用的密钥文件
+func testKeyrings(t *testing.T) map[string]func() (*secrets.Keyring, string) {
+	t.Helper()
+	t.Setenv("DEPLOY_SECRET_KEY", "")
+	t.Setenv("DEPLOY_AGE_IDENTITY", "")
+
+	return map[string]func() (*secrets.Keyring, string){
+		secrets.AlgorithmAES: func() (*secrets.Keyring, string) {
+			keyFile := filepath.Join(t.TempDir(), "secret.key")
+			if err := secrets.GenerateKey(keyFile); err != nil {
+				t.Fatal(err)
+			}
+			return secrets.New(secrets.Options{KeyFile: keyFile}), keyFile
+		},
+		secrets.AlgorithmAge: func() (*secrets.Keyring, string) {
+			identity := filepath.Join(t.TempDir(), "age.key")
+			recipient, err := secrets.GenerateIdentity(identity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return secrets.New(secrets.Options{AgeRecipients: []string{recipient}, AgeIdentity: identity}), identity
+		},
+	}
+}
+
+func TestUnsealAndSeal(t *testing.T) {
+	for algorithm, newKeyring := range testKeyrings(t) {
+		t.Run(algorithm, func(t *testing.T) {
+			keyring, _ := newKeyring()
+			other, _ := newKeyring()
+			password := encrypt(t, keyring, "db-password")
+			token := encrypt(t, keyring, "api-token")
+			foreign := encrypt(t, other, "other-key")
+			if !strings.HasPrefix(password, "ENC["+algorithm+",") {
+				t.Fatalf("加密值应使用 %s: %s", algorithm, password)
+			}
+
+			data := fmt.Sprintf("variables:\n  DB_PASSWORD: %s\n  API_TOKEN: %s\n  FOREIGN: %s\n  PLAIN: value\n", password, token, foreign)
+			content, document, err := UnsealSecrets([]byte(data), keyring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, expected := range []string{"DB_PASSWORD: !secret db-password", "API_TOKEN: !secret api-token", "FOREIGN: " + foreign, "PLAIN: value"} {
+				if !strings.Contains(string(content), expected) {
+					t.Errorf("解密后的内容中缺少 %q:\n%s", expected, content)
+				}
+			}
+			if len(document.Skipped) != 1 || !strings.HasPrefix(document.Skipped[0], "variables.FOREIGN (第 4 行)") {
+				t.Errorf("无法解密的值应记录在 Skipped 中: %q", document.Skipped)
+			}
+
+			// 修改一个值并新增一个值，未修改的值保留原密文
+			edited := strings.Replace(string(content), "!secret api-token", "!secret new-token", 1) + "  ADDED: !secret added\n"
+			sealed, changed, err := document.Seal([]byte(edited))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != 2 {
+				t.Errorf("新加密的值为 %d 个，应为 2", changed)
+			}
+
+			var values struct {
+				Variables map[string]string `yaml:"variables"`
+			}
+			if err := yaml.Unmarshal(sealed, &values); err != nil {
+				t.Fatal(err)
+			}
+			expected := map[string]string{"API_TOKEN": "new-token", "ADDED": "added"}
+			for name, plaintext := range expected {
+				if decrypted, err := keyring.Decrypt(values.Variables[name]); err != nil || decrypted != plaintext {
+					t.Errorf("%s 解密后为 %q (%v)，应为 %q", name, decrypted, err, plaintext)
+				}
+			}
+			unchanged := map[string]string{"DB_PASSWORD": password, "FOREIGN": foreign, "PLAIN": "value"}
+			for name, value := range unchanged {
+				if values.Variables[name] != value {
+					t.Errorf("%s 为 %q，应保持为 %q", name, values.Variables[name], value)
+				}
+			}
+			if strings.Contains(string(sealed), SecretTag) {
+				t.Errorf("加密后不应保留 %s 标签:\n%s", SecretTag, sealed)
+			}
+		})
+	}
+}
+
+func TestResolveEnvironmentDecryptsCurrentEnvironment(t *testing.T) {
+	t.Setenv("DEPLOY_USER_CONFIG", "")
+	// 通过 secrets 配置引用解密使用的密钥文件
+	secretsConfig := map[string]string{
+		secrets.AlgorithmAES: "secrets:\n  key_file: %s\n",
+		secrets.AlgorithmAge: "secrets:\n  age_identity: %s\n",
+	}
+
+	for algorithm, newKeyring := range testKeyrings(t) {
+		t.Run(algorithm, func(t *testing.T) {
+			keyring, keyPath := newKeyring()
+			other, _ := newKeyring()
+			path := filepath.Join(t.TempDir(), "deploy.yaml")
+			content := fmt.Sprintf("version: 2\n"+secretsConfig[algorithm]+"environments:\n  prod:\n    scripts:\n      variables:\n        DB_PASSWORD: %s\n  staging:\n    scripts:\n      variables:\n        DB_PASSWORD: %s\n",
+				keyPath, encrypt(t, keyring, "prod-password"), encrypt(t, other, "staging-password"))
+			writeConfigFile(t, path, content)
+
+			cfg, err := LoadEnvironmentConfig(path, "prod", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value := cfg.Environments["prod"].Scripts.Variables["DB_PASSWORD"]; value != "prod-password" {
+				t.Errorf("当前环境的值应被解密，实际为 %q", value)
+			}
+			if value := cfg.Environments["staging"].Scripts.Variables["DB_PASSWORD"]; !secrets.IsEncrypted(value) {
+				t.Errorf("其他环境的值应保持加密，实际为 %q", value)
+			}
+
+			// 当前环境的值无法解密时报错，并指出文件和行号
+			if _, err := LoadEnvironmentConfig(path, "staging", true); err == nil || !strings.Contains(err.Error(), "deploy.yaml:12: 解密 environments.staging.scripts.variables.DB_PASSWORD 失败") {
+				t.Errorf("应返回包含位置的解密错误，实际为 %v", err)
+			}
+		})
+	}
+}
+
+// encrypt 加密测试值
+func encrypt(t *testing.T, keyring *secrets.Keyring, plaintext string) string {
+	t.Helper()
+	value, err := keyring.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// JavaCommandData Java 命令模板变量
+type JavaCommandData struct {
+	HeapMin    string
+	HeapMax    string
+	JvmOptions string
+	JarFile    string
+	AppOptions string
+	LogFile    string
+	PidFile    string
+}
+
+// CommandData 根据 Java 运行时配置生成命令模板变量
+func (j *JavaConfig) CommandData(jarFile, logFile, pidFile string) JavaCommandData {
+	return JavaCommandData{
+		HeapMin:    j.Runtime.HeapSize.Min,
+		HeapMax:    j.Runtime.HeapSize.Max,
+		JvmOptions: strings.Join(j.Runtime.JvmOptions, " "),
+		JarFile:    jarFile,
+		AppOptions: strings.Join(j.Runtime.AppOptions, " "),
+		LogFile:    logFile,
+		PidFile:    pidFile,
+	}
+}
+
+// RenderTemplate 渲染模板，缺少变量时返回错误
+func RenderTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("解析模板 %s 失败: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("渲染模板 %s 失败: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,648 @@
+package config
+
+import (
+	"deploy/internal/utils"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue 配置校验发现的问题
+type Issue struct {
+	Line    int    // YAML 行号，无法定位时为 0
+	Column  int    // YAML 列号
+	Path    string // 配置项路径，例如 environments.prod.servers[0].host
+	Message string
+	Warning bool // 警告不影响校验结果
+}
+
+// String 格式化为 行:列: 配置项: 描述
+func (i Issue) String() string {
+	var parts []string
+	if i.Line > 0 {
+		parts = append(parts, fmt.Sprintf("%d:%d", i.Line, i.Column))
+	}
+	if i.Path != "" {
+		parts = append(parts, i.Path)
+	}
+	parts = append(parts, i.Message)
+	return strings.Join(parts, ": ")
+}
+
+// HasErrors 检查是否存在错误（警告除外）
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if !issue.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+// enumValues 取值受限的配置项，路径中 * 表示环境名等映射键，[] 表示列表元素，空值表示使用默认值
+var enumValues = map[string][]string{
+	"java.build_tool":                        {"maven", "gradle"},
+	"python.mode":                            {"bundle", "wheel"},
+	"artifact.format":                        {"tar.gz", "tar.zst", "zip", "dir", "jar"},
+	"cache.scope":                            {"global", "project"},
+	"docker.cli":                             {"docker", "podman", "buildah"},
+	"publish.npm.mode":                       {"package", "artifact"},
+	"publish.npm.access":                     {"public", "restricted"},
+	"environments.*.mode":                    {"static", "service"},
+	"environments.*.service.manager":         {"nohup", "systemd", "pm2"},
+	"environments.*.service.systemd.scope":   {"system", "user"},
+	"environments.*.service.pm2.exec_mode":   {"fork", "cluster"},
+	"environments.*.service.systemd.restart": {"no", "always", "on-success", "on-failure", "on-abnormal", "on-abort", "on-watchdog"},
+}
+
+// portFields 端口配置项，配置后取值范围为 1-65535
+var portFields = map[string]bool{
+	"environments.*.servers[].port":      true,
+	"environments.*.service_port":        true,
+	"environments.*.static.nginx.listen": true,
+	"docker.port":                        true,
+	"ssh.port":                           true,
+}
+
+// rulePath 获取匹配 enumValues 和 portFields 的路径，环境中的覆盖配置使用顶层配置的规则
+func rulePath(pattern string) string {
+	rest := strings.TrimPrefix(pattern, "environments.*.")
+	if rest == pattern {
+		return pattern
+	}
+	section := strings.SplitN(rest, ".", 2)[0]
+	if contains(overlaySections, section) {
+		return rest
+	}
+	return pattern
+}
+
+// heapSizePattern 堆内存大小，例如 512m、2g
+var heapSizePattern = regexp.MustCompile(`^[0-9]+[kKmMgGtT]?$`)
+
+// validator 配置校验器
+type validator struct {
+	baseDir      string                // 解析相对路径的目录
+	nodes        map[string]*yaml.Node // 配置项路径对应的值节点，用于定位行号
+	issues       []Issue
+	typesOnly    bool // 只检查类型，不检查枚举值和端口范围
+	allowUnknown bool // 忽略未知的配置项
+	layer        bool // 配置层文件，只包含部分配置项，不检查必填项
+	userConfig   bool // 用户级配置，未指定 version 时按当前版本处理
+}
+
+// ValidateFile 校验配置文件，返回发现的所有问题；文件无法读取或格式错误时返回错误
+//
+// TOML 配置文件转换为 YAML 后校验，报告的问题没有行号。
+func ValidateFile(configPath string) ([]Issue, error) {
+	return validateFile(configPath, &validator{baseDir: filepath.Dir(configPath)}, "")
+}
+
+// ValidateLayerFile 校验与 configPath 一起加载的配置层文件：用户级配置或 deploy.<环境>.yaml
+//
+// 配置层只包含部分配置项，不检查各环境的必填项；引用的文件相对 configPath 所在目录。
+// envName 为 deploy.<环境>.yaml 对应的环境，替换变量时同时使用 .env.<环境>。
+func ValidateLayerFile(path, configPath, envName string) ([]Issue, error) {
+	v := &validator{baseDir: filepath.Dir(configPath), layer: true, userConfig: path == UserConfigPath()}
+	return validateFile(path, v, envName)
+}
+
+// validateFile 读取配置文件并校验
+func validateFile(path string, v *validator, envName string) ([]Issue, error) {
+	if IsTOML(path) {
+		root, err := readDocument(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := yaml.Marshal(root)
+		if err != nil {
+			return nil, fmt.Errorf("解析配置文件失败: %w", err)
+		}
+		issues, err := v.validate(data, envName)
+		// 转换后的行号与 TOML 文件不对应
+		for i := range issues {
+			issues[i].Line, issues[i].Column = 0, 0
+		}
+		return issues, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	return v.validate(data, envName)
+}
+
+// Validate 校验 YAML 配置内容，baseDir 为模板、配置文件等相对路径的根目录
+//
+// 检查未知配置项、类型、枚举值、端口范围、各环境的必填项、堆内存大小、
+// 命令模板变量以及引用的文件。
+func Validate(data []byte, baseDir string) ([]Issue, error) {
+	v := &validator{baseDir: baseDir}
+	return v.validate(data, "")
+}
+
+// validate 校验 YAML 配置内容，变量来自 baseDir 中的 .env 以及 envName 对应的 .env.<环境>
+func (v *validator) validate(data []byte, envName string) ([]Issue, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	v.nodes = make(map[string]*yaml.Node)
+	if len(root.Content) == 0 {
+		v.issues = append(v.issues, Issue{Message: "配置文件为空"})
+		return v.issues, nil
+	}
+
+	vars, err := LoadVariables(v.baseDir, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	document := root.Content[0]
+	v.issues = append(v.issues, expandVariables(document, "", vars)...)
+	v.walk(document, reflect.TypeOf(Config{}), "", "")
+
+	// 类型错误已在上面报告，解码时出错的配置项保持零值，其余配置项仍会被解析
+	var cfg Config
+	_ = document.Decode(&cfg)
+	v.check(&cfg)
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		return v.issues[i].Line < v.issues[j].Line
+	})
+	return v.issues, nil
+}
+
+// checkStructure 检查配置节点的类型，strict 为 true 时同时检查未知的配置项，加载配置时使用
+func checkStructure(root *yaml.Node, strict bool) []Issue {
+	v := &validator{nodes: make(map[string]*yaml.Node), typesOnly: true, allowUnknown: !strict}
+	v.walk(root, reflect.TypeOf(Config{}), "", "")
+	return v.issues
+}
+
+// walk 按配置结构检查 YAML 节点：未知配置项、类型、枚举值和端口范围
+//
+// path 为显示的配置项路径，pattern 为匹配 enumValues 和 portFields 的路径。
+func (v *validator) walk(node *yaml.Node, t reflect.Type, path, pattern string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	v.nodes[path] = node
+	if node.ShortTag() == "!!null" {
+		return
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if !v.expect(node, yaml.MappingNode, path, "对象") {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				v.walkMerge(value, t, path, pattern)
+				continue
+			}
+
+			childPath := joinPath(path, key.Value)
+			field, ok := fields[key.Value]
+			if !ok && v.allowUnknown {
+				continue
+			}
+			if !ok {
+				message := fmt.Sprintf("未知的配置项 %s", key.Value)
+				if suggestion := closestName(key.Value, fields); suggestion != "" {
+					message += fmt.Sprintf("，是否为 %s?", suggestion)
+				}
+				v.add(key, childPath, false, message)
+				continue
+			}
+			v.walk(value, field.Type, childPath, joinPath(pattern, key.Value))
+		}
+
+	case reflect.Map:
+		if !v.expect(node, yaml.MappingNode, path, "对象") {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			v.walk(value, t.Elem(), joinPath(path, key.Value), joinPath(pattern, "*"))
+		}
+
+	case reflect.Slice:
+		if !v.expect(node, yaml.SequenceNode, path, "列表") {
+			return
+		}
+		for i, item := range node.Content {
+			v.walk(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), pattern+"[]")
+		}
+
+	case reflect.String:
+		if !v.expect(node, yaml.ScalarNode, path, "字符串") {
+			return
+		}
+		if values, ok := enumValues[rulePath(pattern)]; ok && !v.typesOnly && node.Value != "" && !contains(values, node.Value) {
+			v.add(node, path, false, fmt.Sprintf("取值无效: %s (可选 %s)", node.Value, strings.Join(values, ", ")))
+		}
+
+	case reflect.Int:
+		if !v.expect(node, yaml.ScalarNode, path, "整数") {
+			return
+		}
+		n, err := strconv.ParseInt(node.Value, 0, 64)
+		if node.ShortTag() != "!!int" || err != nil {
+			v.add(node, path, false, fmt.Sprintf("类型错误，应为整数: %s", node.Value))
+			return
+		}
+		if portFields[rulePath(pattern)] && !v.typesOnly && (n < 1 || n > 65535) {
+			v.add(node, path, false, fmt.Sprintf("端口超出范围: %d (1-65535)", n))
+		}
+
+	case reflect.Bool:
+		if !v.expect(node, yaml.ScalarNode, path, "布尔值") {
+			return
+		}
+		if node.ShortTag() != "!!bool" {
+			v.add(node, path, false, fmt.Sprintf("类型错误，应为 true 或 false: %s", node.Value))
+		}
+	}
+}
+
+// walkMerge 检查 YAML 合并键 (<<) 引用的对象
+func (v *validator) walkMerge(node *yaml.Node, t reflect.Type, path, pattern string) {
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			v.walk(item, t, path, pattern)
+		}
+		return
+	}
+	v.walk(node, t, path, pattern)
+}
+
+// expect 检查节点类型
+func (v *validator) expect(node *yaml.Node, kind yaml.Kind, path, name string) bool {
+	if node.Kind == kind {
+		return true
+	}
+	v.add(node, path, false, fmt.Sprintf("类型错误，应为%s", name))
+	return false
+}
+
+// check 检查解析后的配置：项目类型、Java 运行时、引用的文件和各环境配置
+func (v *validator) check(cfg *Config) {
+	switch version := cfg.Version; {
+	case version > CurrentVersion:
+		v.errorf("version", "配置文件版本 %d 高于当前支持的版本 %d，请升级 deploy", version, CurrentVersion)
+	case version == 0 && v.userConfig:
+		// 用户级配置通常手写且不含 version，旧的配置项名称会作为未知配置项报告
+	case version < CurrentVersion:
+		if version == 0 {
+			version = 1
+		}
+		v.warnf("version", "配置文件版本 %d 已过时 (当前为 %d)，运行 deploy config migrate 升级", version, CurrentVersion)
+	}
+
+	if cfg.Project.Type != "" && !utils.IsValidProjectType(cfg.Project.Type) {
+		v.errorf("project.type", "不支持的项目类型: %s (可选 %s)", cfg.Project.Type, strings.Join(utils.ProjectTypes, ", "))
+	}
+
+	v.checkJava("java", &cfg.Java)
+	v.checkArtifact(cfg)
+	v.checkFile("docker.dockerfile", cfg.Docker.Dockerfile)
+	v.checkFile("python.requirements", cfg.Python.Requirements)
+	v.checkKeyFile("ssh.key_file", cfg.SSH.KeyFile)
+
+	names := make([]string, 0, len(cfg.Environments))
+	for name := range cfg.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v.checkEnvironment(cfg, name, cfg.Environments[name])
+	}
+}
+
+// checkKeyFile 检查 SSH 密钥文件是否存在，不存在时只给出警告
+func (v *validator) checkKeyFile(path, keyFile string) {
+	if keyFile == "" {
+		return
+	}
+
+	resolved := keyFile
+	if strings.HasPrefix(resolved, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			resolved = filepath.Join(home, resolved[2:])
+		}
+	}
+	if _, err := os.Stat(resolved); err != nil {
+		v.warnf(path, "密钥文件不存在: %s", keyFile)
+	}
+}
+
+// checkJava 检查堆内存大小和命令模板
+func (v *validator) checkJava(path string, java *JavaConfig) {
+	heap := java.Runtime.HeapSize
+	minPath := path + ".runtime.heap_size.min"
+	maxPath := path + ".runtime.heap_size.max"
+
+	minBytes, minOK := v.heapSize(minPath, heap.Min)
+	maxBytes, maxOK := v.heapSize(maxPath, heap.Max)
+	if minOK && maxOK && minBytes > maxBytes {
+		v.errorf(minPath, "最小堆内存 %s 大于最大堆内存 %s", heap.Min, heap.Max)
+	}
+
+	// 使用示例变量渲染命令模板，检查语法和变量名
+	data := java.CommandData("app.jar", "app.log", "app.pid")
+	commands := []struct {
+		key  string
+		text string
+	}{
+		{"default_start_command", java.DefaultStartCommand},
+		{"default_stop_command", java.DefaultStopCommand},
+		{"default_status_command", java.DefaultStatusCommand},
+	}
+	for _, command := range commands {
+		if command.text == "" {
+			continue
+		}
+		if _, err := RenderTemplate(command.key, command.text, data); err != nil {
+			v.errorf(path+"."+command.key, "%v", err)
+		}
+	}
+}
+
+// heapSize 解析堆内存大小，未配置或格式错误时返回 false
+func (v *validator) heapSize(path, value string) (int64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if !heapSizePattern.MatchString(value) {
+		v.errorf(path, "堆内存大小格式错误: %s (例如 512m、2g)", value)
+		return 0, false
+	}
+
+	unit := int64(1)
+	number := value
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "k":
+		unit = 1 << 10
+	case "m":
+		unit = 1 << 20
+	case "g":
+		unit = 1 << 30
+	case "t":
+		unit = 1 << 40
+	}
+	if unit > 1 {
+		number = value[:len(value)-1]
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		v.errorf(path, "堆内存大小格式错误: %s", value)
+		return 0, false
+	}
+	return n * unit, true
+}
+
+// checkArtifact 检查打包配置中的 glob 模式和引用的文件
+func (v *validator) checkArtifact(cfg *Config) {
+	artifact := cfg.Artifact
+
+	for i, pattern := range artifact.Include {
+		if err := utils.ValidateGlob(pattern); err != nil {
+			v.errorf(fmt.Sprintf("artifact.include[%d]", i), "glob 模式无效: %s", pattern)
+		}
+	}
+	for i, pattern := range artifact.Exclude {
+		if err := utils.ValidateGlob(pattern); err != nil {
+			v.errorf(fmt.Sprintf("artifact.exclude[%d]", i), "glob 模式无效: %s", pattern)
+		}
+	}
+	for i, extra := range artifact.ExtraFiles {
+		path := fmt.Sprintf("artifact.extra_files[%d]", i)
+		if extra.Source == "" {
+			v.errorf(path+".source", "未配置 source")
+			continue
+		}
+		v.checkFile(path+".source", extra.Source)
+	}
+
+	for i, file := range artifact.Bundle.ConfigFiles {
+		v.checkFile(fmt.Sprintf("artifact.bundle.config_files[%d]", i), file)
+	}
+	v.checkTemplateFile("artifact.bundle.application_template", artifact.Bundle.ApplicationTemplate)
+}
+
+// checkEnvironment 检查环境配置
+func (v *validator) checkEnvironment(cfg *Config, name string, env EnvironmentConfig) {
+	path := "environments." + name
+
+	if env.DeployPath == "" && !v.layer {
+		static := env.Static
+		if env.Mode != "static" || static.WebRoot == "" || static.ReleasesDir == "" {
+			v.errorf(path+".deploy_path", "环境 %s 未配置 deploy_path", name)
+		}
+	}
+
+	for i, server := range env.Servers {
+		serverPath := fmt.Sprintf("%s.servers[%d]", path, i)
+		if strings.TrimSpace(server.Host) == "" {
+			v.errorf(serverPath+".host", "服务器地址不能为空")
+		}
+		v.checkKeyFile(serverPath+".key_file", server.KeyFile)
+		switch host := strings.TrimSpace(server.Host); host {
+		case "", "localhost", "127.0.0.1", "::1":
+		default:
+			v.warnf(serverPath+".host", "部署只能在本机执行，配置远程服务器 %s 时 deploy release 会报错", host)
+		}
+	}
+
+	if env.HealthCheckURL != "" {
+		u, err := url.Parse(env.HealthCheckURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.errorf(path+".health_check_url", "健康检查地址无效: %s", env.HealthCheckURL)
+		}
+	}
+
+	if env.Mode == "service" && env.ServiceName == "" && cfg.Project.Name == "" && !v.layer {
+		v.errorf(path+".service_name", "环境 %s 未配置 service_name，且 project.name 为空", name)
+	}
+	if env.Service.Systemd.User != "" && env.Service.Systemd.Scope == "user" {
+		v.warnf(path+".service.systemd.user", "user 仅在 system 范围有效，user 范围下将被忽略")
+	}
+
+	if env.Java != nil {
+		v.checkJava(path+".java", env.Java)
+	}
+	v.checkTemplateFile(path+".static.nginx.template", env.Static.Nginx.Template)
+}
+
+// checkFile 检查引用的文件或目录是否存在，返回解析后的路径
+func (v *validator) checkFile(path, file string) (string, bool) {
+	if file == "" {
+		return "", false
+	}
+
+	resolved := file
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(v.baseDir, file)
+	}
+	if _, err := os.Stat(resolved); err != nil {
+		v.errorf(path, "文件不存在: %s", file)
+		return "", false
+	}
+	return resolved, true
+}
+
+// checkTemplateFile 检查模板文件是否存在以及模板语法
+func (v *validator) checkTemplateFile(path, file string) {
+	resolved, ok := v.checkFile(path, file)
+	if !ok {
+		return
+	}
+
+	text, err := os.ReadFile(resolved)
+	if err != nil {
+		v.errorf(path, "读取模板失败: %v", err)
+		return
+	}
+	if _, err := template.New(filepath.Base(resolved)).Parse(string(text)); err != nil {
+		v.errorf(path, "模板语法错误: %v", err)
+	}
+}
+
+// errorf 添加错误，定位到配置项或最近的上级配置项
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.add(v.lookup(path), path, false, fmt.Sprintf(format, args...))
+}
+
+// warnf 添加警告
+func (v *validator) warnf(path, format string, args ...interface{}) {
+	v.add(v.lookup(path), path, true, fmt.Sprintf(format, args...))
+}
+
+// add 添加问题
+func (v *validator) add(node *yaml.Node, path string, warning bool, message string) {
+	issue := Issue{Path: path, Message: message, Warning: warning}
+	if node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	v.issues = append(v.issues, issue)
+}
+
+// lookup 查找配置项对应的节点，配置项不存在时返回最近的上级配置项
+func (v *validator) lookup(path string) *yaml.Node {
+	for {
+		if node, ok := v.nodes[path]; ok {
+			return node
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			return v.nodes[""]
+		}
+		path = path[:i]
+	}
+}
+
+// yamlFields 获取结构体字段，键为 yaml 标签中的名称
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := yamlName(field); name != "" {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+// yamlName 获取字段的 yaml 名称，忽略的字段返回空字符串
+func yamlName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// closestName 查找与未知配置项最接近的配置项，用于提示拼写错误
+func closestName(name string, fields map[string]reflect.StructField) string {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+
+	best, bestDistance := "", 3
+	for candidate := range fields {
+		if strings.ReplaceAll(candidate, "_", "") == normalized {
+			return candidate
+		}
+		if d := editDistance(name, candidate); d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串的编辑距离
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// minInt 返回最小值
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+// joinPath 拼接配置项路径
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// contains 检查列表中是否包含指定值
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
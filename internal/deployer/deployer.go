@@ -0,0 +1,96 @@
+package deployer
+
+import (
+	"deploy/internal/config"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// ModeStatic 静态站点部署
+	ModeStatic = "static"
+	// ModeService Java/Node 服务部署
+	ModeService = "service"
+)
+
+// Options 部署选项
+type Options struct {
+	Environment  string // 环境名称，对应 environments 中的键
+	ArtifactPath string // 构建产物路径
+	Version      string // 构建产物版本号
+	Builder      string // 生成构建产物的构建器，用于区分 Java 和 Node 服务
+	ProjectPath  string // 项目目录，用于查找模板等项目文件
+	Verbose      bool
+}
+
+// Deploy 按环境的部署模式部署构建产物
+func Deploy(cfg *config.Config, options *Options) error {
+	env, ok := cfg.Environments[options.Environment]
+	if !ok {
+		return fmt.Errorf("环境不存在: %s", options.Environment)
+	}
+	if err := CheckLocal(options.Environment, env); err != nil {
+		return err
+	}
+
+	switch env.Mode {
+	case ModeStatic:
+		return newStaticDeployer(cfg, &env, options).Deploy()
+	case ModeService:
+		return newServiceDeployer(cfg, &env, options).Deploy()
+	case "":
+		return fmt.Errorf("环境 %s 未配置部署模式 (environments.%s.mode)", options.Environment, options.Environment)
+	default:
+		return fmt.Errorf("不支持的部署模式: %s (可选 %s, %s)", env.Mode, ModeStatic, ModeService)
+	}
+}
+
+// CheckLocal 检查环境是否部署到本机，部署和服务管理都在本机执行，不支持通过 SSH 操作远程服务器
+func CheckLocal(name string, env config.EnvironmentConfig) error {
+	var remote []string
+	for _, server := range env.Servers {
+		switch strings.TrimSpace(server.Host) {
+		case "localhost", "127.0.0.1", "::1":
+		default:
+			remote = append(remote, server.Host)
+		}
+	}
+	if len(remote) == 0 {
+		return nil
+	}
+	return fmt.Errorf("环境 %s 配置了远程服务器 (%s)，部署只能在本机执行: 请在目标服务器上运行 deploy，或删除 environments.%s.servers", name, strings.Join(remote, ", "), name)
+}
+
+// runShell 使用配置的 shell 在指定目录执行命令，dir 为空时使用当前目录
+func runShell(cfg *config.Config, dir, command string, verbose bool) ([]byte, error) {
+	shell := cfg.Scripts.Global.Shell
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	cmd := exec.Command(shell, "-c", command)
+	cmd.Dir = dir
+	if verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return nil, cmd.Run()
+	}
+	return cmd.CombinedOutput()
+}
+
+// runCommand 执行命令，失败时在错误中包含命令输出
+func runCommand(verbose bool, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+	return nil
+}
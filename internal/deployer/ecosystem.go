@@ -0,0 +1,108 @@
+package deployer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// ecosystemApp PM2 ecosystem 中的应用配置
+type ecosystemApp struct {
+	Name             string            `json:"name"`
+	Cwd              string            `json:"cwd"`
+	Script           string            `json:"script"`
+	Args             string            `json:"args,omitempty"`
+	Instances        interface{}       `json:"instances"`
+	ExecMode         string            `json:"exec_mode"`
+	MaxMemoryRestart string            `json:"max_memory_restart,omitempty"`
+	OutFile          string            `json:"out_file"`
+	ErrorFile        string            `json:"error_file"`
+	MergeLogs        bool              `json:"merge_logs"`
+	Env              map[string]string `json:"env"`
+}
+
+// EcosystemPath 生成的 ecosystem 文件路径，位于版本目录之外，不会覆盖构建产物中的同名文件
+func (s *Service) EcosystemPath() string {
+	return filepath.Join(s.DeployPath, "ecosystem.config.js")
+}
+
+// EcosystemEnvPath ecosystem 文件加载的环境变量文件，内容为 scripts.variables 的 JSON
+func (s *Service) EcosystemEnvPath() string {
+	return s.EnvFile() + ".json"
+}
+
+// WriteEcosystemEnv 将 scripts.variables 写入权限为 0600 的 EcosystemEnvPath，没有变量时删除该文件
+func (s *Service) WriteEcosystemEnv() error {
+	var data []byte
+	if len(s.Env.Scripts.Variables) > 0 {
+		var err error
+		if data, err = json.MarshalIndent(s.Env.Scripts.Variables, "", "  "); err != nil {
+			return fmt.Errorf("生成环境变量文件失败: %w", err)
+		}
+		data = append(data, '\n')
+	}
+	return writePrivateFile(s.EcosystemEnvPath(), data)
+}
+
+// RenderEcosystem 根据环境配置生成 PM2 ecosystem 文件
+//
+// 应用名称为 service_name，cwd 为 current 符号链接。ecosystem 文件所有用户可读，
+// env 不直接写入 scripts.variables，而是在加载时读取 EcosystemEnvPath。
+func (s *Service) RenderEcosystem() (string, error) {
+	pm2 := s.Env.Service.PM2
+
+	app := ecosystemApp{
+		Name:             s.Name,
+		Cwd:              s.Current,
+		Script:           pm2.Script,
+		Args:             pm2.Args,
+		ExecMode:         pm2.ExecMode,
+		MaxMemoryRestart: pm2.MaxMemoryRestart,
+		MergeLogs:        true,
+		Env:              map[string]string{},
+	}
+	if app.Script == "" {
+		app.Script = "npm"
+		if app.Args == "" {
+			app.Args = "start"
+		}
+	}
+	if app.ExecMode == "" {
+		app.ExecMode = "fork"
+	}
+	if app.ExecMode != "fork" && app.ExecMode != "cluster" {
+		return "", fmt.Errorf("不支持的 PM2 exec_mode: %s (可选 fork, cluster)", app.ExecMode)
+	}
+	// 数字实例数按数字输出，其他值 (例如 max) 按字符串输出
+	app.Instances = 1
+	if pm2.Instances != "" {
+		if n, err := strconv.Atoi(pm2.Instances); err == nil {
+			app.Instances = n
+		} else {
+			app.Instances = pm2.Instances
+		}
+	}
+
+	logDir := pm2.LogDir
+	if logDir == "" {
+		logDir = filepath.Join(s.DeployPath, "logs")
+	}
+	app.OutFile = filepath.Join(logDir, s.Name+".out.log")
+	app.ErrorFile = filepath.Join(logDir, s.Name+".error.log")
+
+	data, err := json.MarshalIndent(map[string][]ecosystemApp{"apps": {app}}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("生成 ecosystem 文件失败: %w", err)
+	}
+
+	if len(s.Env.Scripts.Variables) == 0 {
+		return fmt.Sprintf("// 由 deploy 生成，重新部署时会被覆盖\nmodule.exports = %s;\n", data), nil
+	}
+
+	envPath, err := json.Marshal(s.EcosystemEnvPath())
+	if err != nil {
+		return "", fmt.Errorf("生成 ecosystem 文件失败: %w", err)
+	}
+	return fmt.Sprintf("// 由 deploy 生成，重新部署时会被覆盖\nconst config = %s;\n\n// scripts.variables 保存在权限为 0600 的文件中\nconfig.apps[0].env = require(%s);\n\nmodule.exports = config;\n", data, envPath), nil
+}
@@ -0,0 +1,205 @@
+package deployer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"deploy/internal/utils"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// extractArtifact 将构建产物解压到目标目录，支持 tar.gz、tar.zst、zip 和目录格式
+func extractArtifact(artifactPath, dest string) error {
+	info, err := os.Stat(artifactPath)
+	if err != nil {
+		return fmt.Errorf("构建产物不存在: %w", err)
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	switch {
+	case info.IsDir():
+		return copyDir(artifactPath, dest)
+	case strings.HasSuffix(artifactPath, ".tar.gz"), strings.HasSuffix(artifactPath, ".tgz"):
+		return extractTarGz(artifactPath, dest)
+	case strings.HasSuffix(artifactPath, ".tar.zst"):
+		return extractTarZst(artifactPath, dest)
+	case strings.HasSuffix(artifactPath, ".zip"):
+		return extractZip(artifactPath, dest)
+	default:
+		return fmt.Errorf("不支持解压的构建产物格式: %s", filepath.Base(artifactPath))
+	}
+}
+
+// extractTarGz 解压 tar.gz
+func extractTarGz(artifactPath, dest string) error {
+	file, err := os.Open(artifactPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", filepath.Base(artifactPath), err)
+	}
+	defer gzReader.Close()
+
+	return extractTar(tar.NewReader(gzReader), dest)
+}
+
+// extractTarZst 解压 tar.zst，解压缩由系统的 zstd 命令完成
+func extractTarZst(artifactPath, dest string) error {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return fmt.Errorf("解压 tar.zst 需要 zstd 命令，但未安装或不在 PATH 中")
+	}
+
+	cmd := exec.Command("zstd", "-dc", artifactPath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动 zstd 失败: %w", err)
+	}
+
+	if err := extractTar(tar.NewReader(stdout), dest); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("zstd 解压失败: %w", err)
+	}
+	return nil
+}
+
+// extractTar 解压 tar 流
+func extractTar(reader *tar.Reader, dest string) error {
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取压缩包失败: %w", err)
+		}
+
+		target, err := extractTarget(dest, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeExtractedFile(target, reader, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractZip 解压 zip
+func extractZip(artifactPath, dest string) error {
+	reader, err := zip.OpenReader(artifactPath)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", filepath.Base(artifactPath), err)
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		target, err := extractTarget(dest, file.Name)
+		if err != nil {
+			return err
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = writeExtractedFile(target, src, file.Mode().Perm())
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractTarget 获取压缩包内文件的解压路径，拒绝指向目标目录之外的路径
+func extractTarget(dest, name string) (string, error) {
+	target := filepath.Join(dest, filepath.FromSlash(strings.TrimPrefix(name, "./")))
+	relPath, err := filepath.Rel(dest, target)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("压缩包中的路径无效: %s", name)
+	}
+	return target, nil
+}
+
+// writeExtractedFile 写入解压的文件
+func writeExtractedFile(target string, src io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, src)
+	return err
+}
+
+// copyDir 复制目录格式的构建产物
+func copyDir(src, dest string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, relPath)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, 0755)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return utils.CopyFile(path, target)
+		}
+	})
+}
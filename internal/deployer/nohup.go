@@ -0,0 +1,124 @@
+package deployer
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/utils"
+	"fmt"
+	"strings"
+)
+
+// nohupManager 使用 default_start_command、default_stop_command 等命令管理服务
+//
+// Java 服务的命令为模板，可以使用 .HeapMin、.JarFile、.PidFile 等变量；
+// Node 服务的命令在当前版本目录中执行。
+type nohupManager struct {
+	service *Service
+}
+
+// Name 管理器名称
+func (m *nohupManager) Name() string {
+	return ManagerNohup
+}
+
+// Install 无需安装
+func (m *nohupManager) Install() error {
+	return nil
+}
+
+// Start 启动服务
+func (m *nohupManager) Start() error {
+	command, err := m.command("start")
+	if err != nil {
+		return err
+	}
+	if command == "" {
+		return fmt.Errorf("未配置启动命令 (default_start_command)")
+	}
+
+	fmt.Printf("▶️  启动 %s...\n", m.service.Name)
+	if err := m.run(command); err != nil {
+		return fmt.Errorf("启动服务失败: %w", err)
+	}
+	return nil
+}
+
+// Stop 停止服务
+func (m *nohupManager) Stop() error {
+	command, err := m.command("stop")
+	if err != nil {
+		return err
+	}
+	if command == "" {
+		return fmt.Errorf("未配置停止命令 (default_stop_command)")
+	}
+
+	fmt.Printf("⏹️  停止 %s...\n", m.service.Name)
+	if err := m.run(command); err != nil {
+		return fmt.Errorf("停止服务失败: %w", err)
+	}
+	return nil
+}
+
+// Restart 停止后启动，停止失败（例如服务未运行）时只给出警告
+func (m *nohupManager) Restart() error {
+	if err := m.Stop(); err != nil {
+		utils.PrintWarning(err.Error())
+	}
+	return m.Start()
+}
+
+// Status 执行 default_status_command，退出码为 0 时表示运行中
+func (m *nohupManager) Status() (string, error) {
+	command, err := m.command("status")
+	if err != nil {
+		return "", err
+	}
+	if command == "" {
+		return "", fmt.Errorf("未配置状态命令 (default_status_command)")
+	}
+
+	if _, err := runShell(m.service.Config, m.service.Current, command, false); err != nil {
+		return "未运行", nil
+	}
+	return "运行中", nil
+}
+
+// command 获取渲染后的命令
+func (m *nohupManager) command(action string) (string, error) {
+	switch m.service.Kind {
+	case serviceJava:
+		java := m.service.Java()
+		templates := map[string]string{
+			"start":  java.DefaultStartCommand,
+			"stop":   java.DefaultStopCommand,
+			"status": java.DefaultStatusCommand,
+		}
+		if templates[action] == "" {
+			return "", nil
+		}
+		data := java.CommandData(m.service.JarFile(), m.service.LogFile(), m.service.PidFile())
+		return config.RenderTemplate(action, templates[action], data)
+
+	case serviceNode:
+		npm := m.service.Config.NPM
+		switch action {
+		case "start":
+			return npm.DefaultStartCommand, nil
+		case "stop":
+			return npm.DefaultStopCommand, nil
+		}
+		return "", nil
+
+	default:
+		return "", fmt.Errorf("无法判断服务类型，请在 project.type 中指定 npm、maven 或 gradle")
+	}
+}
+
+// run 在当前版本目录中执行命令
+func (m *nohupManager) run(command string) error {
+	output, err := runShell(m.service.Config, m.service.Current, command, m.service.Verbose)
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package deployer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// pm2Manager 使用 PM2 管理 Node 服务
+//
+// 服务由生成的 ecosystem 文件定义，使用 pm2 startOrReload 启动或重新加载，
+// cluster 模式下重新加载不会中断服务。
+type pm2Manager struct {
+	service *Service
+}
+
+// Name 管理器名称
+func (m *pm2Manager) Name() string {
+	return ManagerPM2
+}
+
+// Install 生成环境变量文件和 ecosystem 文件
+func (m *pm2Manager) Install() error {
+	content, err := m.service.RenderEcosystem()
+	if err != nil {
+		return err
+	}
+	if err := m.service.WriteEcosystemEnv(); err != nil {
+		return err
+	}
+
+	path := m.service.EcosystemPath()
+	if err := os.MkdirAll(m.service.DeployPath, 0755); err != nil {
+		return fmt.Errorf("创建部署目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入 ecosystem 文件失败: %w", err)
+	}
+	fmt.Printf("📝 已生成 PM2 ecosystem 文件: %s\n", path)
+	return nil
+}
+
+// Start 启动服务，已运行时重新加载
+func (m *pm2Manager) Start() error {
+	fmt.Printf("▶️  启动 %s...\n", m.service.Name)
+	return m.startOrReload()
+}
+
+// Stop 停止服务
+func (m *pm2Manager) Stop() error {
+	fmt.Printf("⏹️  停止 %s...\n", m.service.Name)
+	return m.pm2("stop", m.service.Name)
+}
+
+// Restart 重新加载服务，未运行时启动
+func (m *pm2Manager) Restart() error {
+	fmt.Printf("🔄 重新加载 %s...\n", m.service.Name)
+	return m.startOrReload()
+}
+
+// Status 根据 pm2 pid 判断服务是否运行
+func (m *pm2Manager) Status() (string, error) {
+	output, err := exec.Command("pm2", "pid", m.service.Name).Output()
+	if err != nil {
+		return "", fmt.Errorf("获取服务状态失败: %w", err)
+	}
+
+	pids := strings.Fields(string(output))
+	running := pids[:0]
+	for _, pid := range pids {
+		if pid != "0" {
+			running = append(running, pid)
+		}
+	}
+	if len(running) == 0 {
+		return "未运行", nil
+	}
+	return fmt.Sprintf("运行中 (pid %s)", strings.Join(running, ", ")), nil
+}
+
+// startOrReload 使用 ecosystem 文件启动或重新加载服务，ecosystem 文件不存在时先生成
+func (m *pm2Manager) startOrReload() error {
+	path := m.service.EcosystemPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := m.Install(); err != nil {
+			return err
+		}
+	}
+	return m.pm2("startOrReload", path, "--only", m.service.Name, "--update-env")
+}
+
+// pm2 执行 pm2 命令
+func (m *pm2Manager) pm2(args ...string) error {
+	if err := runCommand(m.service.Verbose, "pm2", args...); err != nil {
+		return fmt.Errorf("pm2 %s 失败: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package deployer
+
+import (
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// releaseLayout 版本目录布局
+//
+// 每个版本解压到 <releases>/<版本号>，current 是指向当前版本的符号链接，
+// 切换版本时通过重命名原子地替换符号链接。
+type releaseLayout struct {
+	current  string // 指向当前版本的符号链接
+	releases string // 版本目录
+	keep     int    // 除当前版本外保留的旧版本数，0 表示不清理
+}
+
+// releaseDir 获取版本对应的目录
+func (l *releaseLayout) releaseDir(version string) string {
+	return filepath.Join(l.releases, utils.SanitizeFileName(version))
+}
+
+// active 获取当前版本的目录，尚未部署时返回空字符串
+func (l *releaseLayout) active() (string, error) {
+	info, err := os.Lstat(l.current)
+	switch {
+	case os.IsNotExist(err):
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("读取 %s 失败: %w", l.current, err)
+	case info.Mode()&os.ModeSymlink == 0:
+		return "", fmt.Errorf("%s 已存在且不是符号链接，请先移走", l.current)
+	}
+
+	target, err := os.Readlink(l.current)
+	if err != nil {
+		return "", fmt.Errorf("读取 %s 失败: %w", l.current, err)
+	}
+	return target, nil
+}
+
+// extract 解压构建产物到版本目录，已存在的同名版本会被替换。JAR、WAR 文件直接复制为 <name>.jar 或 <name>.war
+//
+// 重新部署当前版本时不替换 current 指向的目录，而是解压到 <版本号>-<时间> 目录再切换，
+// 切换前服务仍使用原目录，部署失败时也可以回滚到原目录。
+func (l *releaseLayout) extract(artifactPath, version, name string) (string, error) {
+	fmt.Printf("📦 解压 %s...\n", filepath.Base(artifactPath))
+
+	releaseDir := l.releaseDir(version)
+	active, err := l.active()
+	if err != nil {
+		return "", err
+	}
+	if active != "" && filepath.Clean(active) == filepath.Clean(releaseDir) {
+		releaseDir = fmt.Sprintf("%s-%s", releaseDir, time.Now().Format("20060102150405"))
+	}
+
+	tmpDir := releaseDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return "", fmt.Errorf("清理临时目录失败: %w", err)
+	}
+
+	if ext := filepath.Ext(artifactPath); ext == ".jar" || ext == ".war" {
+		if err = os.MkdirAll(tmpDir, 0755); err == nil {
+			err = utils.CopyFile(artifactPath, filepath.Join(tmpDir, name+ext))
+		}
+	} else {
+		err = extractArtifact(artifactPath, tmpDir)
+	}
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return "", fmt.Errorf("解压构建产物失败: %w", err)
+	}
+
+	if err := os.RemoveAll(releaseDir); err != nil {
+		return "", fmt.Errorf("清理版本目录失败: %w", err)
+	}
+	if err := os.Rename(tmpDir, releaseDir); err != nil {
+		return "", fmt.Errorf("移动版本目录失败: %w", err)
+	}
+	return releaseDir, nil
+}
+
+// activate 将 current 指向指定版本目录，target 为空时移除符号链接
+func (l *releaseLayout) activate(target string) error {
+	if target == "" {
+		if err := os.Remove(l.current); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("移除 %s 失败: %w", l.current, err)
+		}
+		return nil
+	}
+
+	if err := switchSymlink(l.current, target); err != nil {
+		return fmt.Errorf("切换版本失败: %w", err)
+	}
+	fmt.Printf("🔀 %s -> %s\n", l.current, target)
+	return nil
+}
+
+// switchSymlink 原子地将符号链接指向新目录
+func switchSymlink(link, target string) error {
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		return err
+	}
+
+	tmpLink := fmt.Sprintf("%s.tmp-%d", link, os.Getpid())
+	os.Remove(tmpLink)
+	if err := os.Symlink(target, tmpLink); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpLink, link); err != nil {
+		os.Remove(tmpLink)
+		return err
+	}
+	return nil
+}
+
+// prune 清理旧版本，除当前版本外保留最新的 keep 个版本（始终包括上一个版本）
+func (l *releaseLayout) prune(current, previous string) error {
+	keep := l.keep
+	if keep <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(l.releases)
+	if err != nil {
+		return err
+	}
+
+	type release struct {
+		path    string
+		modTime int64
+	}
+	var releases []release
+	for _, entry := range entries {
+		path := filepath.Join(l.releases, entry.Name())
+		if !entry.IsDir() || path == current || path == previous || strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		releases = append(releases, release{path: path, modTime: info.ModTime().UnixNano()})
+	}
+	if previous != "" {
+		keep--
+	}
+	if len(releases) <= keep {
+		return nil
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].modTime > releases[j].modTime
+	})
+	for _, release := range releases[keep:] {
+		if err := os.RemoveAll(release.path); err != nil {
+			return err
+		}
+		fmt.Printf("🗑️  已清理旧版本: %s\n", filepath.Base(release.path))
+	}
+	return nil
+}
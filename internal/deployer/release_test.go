@@ -0,0 +1,50 @@
+package deployer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractKeepsActiveRelease(t *testing.T) {
+	dir := t.TempDir()
+	artifact := filepath.Join(dir, "app-1.0.0.jar")
+	if err := os.WriteFile(artifact, []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	layout := &releaseLayout{current: filepath.Join(dir, "current"), releases: filepath.Join(dir, "releases")}
+
+	first, err := layout.extract(artifact, "1.0.0", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != layout.releaseDir("1.0.0") {
+		t.Fatalf("版本目录为 %s，应为 %s", first, layout.releaseDir("1.0.0"))
+	}
+
+	// 未切换前再次解压，替换同名版本目录
+	again, err := layout.extract(artifact, "1.0.0", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Fatalf("未部署的版本应解压到 %s，实际为 %s", first, again)
+	}
+
+	// 重新部署当前版本时解压到新目录，current 指向的目录保持不变
+	if err := layout.activate(first); err != nil {
+		t.Fatal(err)
+	}
+	redeploy, err := layout.extract(artifact, "1.0.0", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redeploy == first {
+		t.Fatalf("重新部署当前版本时不应替换 %s", first)
+	}
+	for _, release := range []string{first, redeploy} {
+		if _, err := os.Stat(filepath.Join(release, "app.jar")); err != nil {
+			t.Errorf("%s 中缺少 app.jar: %v", release, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package deployer
+
+import (
+	"deploy/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSecret = `p"a$s\w` + "`d"
+
+// newTestService 创建使用 scripts.variables 的服务
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	dir := t.TempDir()
+	env := &config.EnvironmentConfig{DeployPath: dir}
+	env.Scripts.Variables = map[string]string{"DB_PASSWORD": testSecret, "PROFILE": "prod"}
+	env.Service.Systemd.ExecStart = "/usr/bin/env npm start"
+
+	return &Service{
+		Config:     &config.Config{Project: config.ProjectConfig{Name: "app"}},
+		Env:        env,
+		Name:       "app",
+		Kind:       serviceNode,
+		DeployPath: dir,
+		Current:    filepath.Join(dir, "current"),
+	}
+}
+
+func TestSystemdUnitKeepsVariablesPrivate(t *testing.T) {
+	service := newTestService(t)
+	manager := &systemdManager{service: service}
+
+	unit, err := manager.renderUnit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(unit, "DB_PASSWORD") || strings.Contains(unit, testSecret) {
+		t.Fatalf("unit 文件中不应包含变量:\n%s", unit)
+	}
+	if !strings.Contains(unit, "EnvironmentFile="+service.EnvFile()+"\n") {
+		t.Fatalf("unit 文件应引用 %s:\n%s", service.EnvFile(), unit)
+	}
+
+	if err := writePrivateFile(service.EnvFile(), manager.renderEnvironmentFile()); err != nil {
+		t.Fatal(err)
+	}
+	assertPrivate(t, service.EnvFile())
+	content, _ := os.ReadFile(service.EnvFile())
+	expected := "DB_PASSWORD=\"p\\\"a\\$s\\\\w\\`d\"\nPROFILE=\"prod\"\n"
+	if string(content) != expected {
+		t.Errorf("EnvironmentFile 内容为 %q，应为 %q", content, expected)
+	}
+}
+
+func TestEcosystemKeepsVariablesPrivate(t *testing.T) {
+	service := newTestService(t)
+
+	content, err := service.RenderEcosystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(content, "DB_PASSWORD") {
+		t.Fatalf("ecosystem 文件中不应包含变量:\n%s", content)
+	}
+	if !strings.Contains(content, `require("`+service.EcosystemEnvPath()+`")`) {
+		t.Fatalf("ecosystem 文件应加载 %s:\n%s", service.EcosystemEnvPath(), content)
+	}
+
+	if err := service.WriteEcosystemEnv(); err != nil {
+		t.Fatal(err)
+	}
+	assertPrivate(t, service.EcosystemEnvPath())
+
+	// 没有变量时删除环境变量文件
+	service.Env.Scripts.Variables = nil
+	if err := service.WriteEcosystemEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(service.EcosystemEnvPath()); !os.IsNotExist(err) {
+		t.Error("没有变量时应删除环境变量文件")
+	}
+}
+
+func TestWritePrivateFileReplacesReadableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.env")
+	if err := os.WriteFile(path, []byte("OLD=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePrivateFile(path, []byte("NEW=1\n")); err != nil {
+		t.Fatal(err)
+	}
+	assertPrivate(t, path)
+}
+
+// assertPrivate 检查文件只有所有者可以读写
+func assertPrivate(t *testing.T, path string) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("%s 权限为 %o，应为 600", path, mode)
+	}
+}
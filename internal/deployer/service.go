@@ -0,0 +1,297 @@
+package deployer
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// ManagerNohup 使用 default_start_command 等命令管理服务
+	ManagerNohup = "nohup"
+	// ManagerSystemd 使用 systemd 管理服务
+	ManagerSystemd = "systemd"
+	// ManagerPM2 使用 PM2 管理 Node 服务
+	ManagerPM2 = "pm2"
+)
+
+// 服务类型
+const (
+	serviceJava = "java"
+	serviceNode = "node"
+)
+
+// ServiceManager 服务管理器
+type ServiceManager interface {
+	// Name 管理器名称
+	Name() string
+	// Install 安装服务，例如写入 systemd unit 文件
+	Install() error
+	// Start 启动服务
+	Start() error
+	// Stop 停止服务
+	Stop() error
+	// Restart 重启服务，未运行时启动
+	Restart() error
+	// Status 获取服务状态
+	Status() (string, error)
+}
+
+// Service 服务信息
+type Service struct {
+	Config     *config.Config
+	Env        *config.EnvironmentConfig
+	Name       string // 服务名称，默认为项目名称
+	Kind       string // java 或 node
+	DeployPath string
+	Current    string // 指向当前版本的符号链接
+	Verbose    bool
+}
+
+// NewService 根据环境配置创建服务信息，builder 为生成构建产物的构建器，为空时根据当前版本判断
+func NewService(cfg *config.Config, envName, builder string, verbose bool) (*Service, error) {
+	env, ok := cfg.Environments[envName]
+	if !ok {
+		return nil, fmt.Errorf("环境不存在: %s", envName)
+	}
+	if err := CheckLocal(envName, env); err != nil {
+		return nil, err
+	}
+	if env.DeployPath == "" {
+		return nil, fmt.Errorf("环境 %s 未配置 deploy_path", envName)
+	}
+
+	service := &Service{
+		Config:     cfg,
+		Env:        &env,
+		Name:       env.ServiceName,
+		DeployPath: env.DeployPath,
+		Current:    filepath.Join(env.DeployPath, "current"),
+		Verbose:    verbose,
+	}
+	if service.Name == "" {
+		service.Name = cfg.Project.Name
+	}
+	service.Kind = service.detectKind(service.Current, builder)
+
+	return service, nil
+}
+
+// detectKind 判断服务类型，builder 为空时根据 dir 中的版本内容判断
+func (s *Service) detectKind(dir, builder string) string {
+	switch builder {
+	case "maven", "gradle":
+		return serviceJava
+	case "npm":
+		return serviceNode
+	}
+
+	if utils.FileExists(javaArchive(dir, s.Config.Project.Name)) {
+		return serviceJava
+	}
+	if utils.FileExists(filepath.Join(dir, "package.json")) {
+		return serviceNode
+	}
+
+	switch s.Config.Project.Type {
+	case "maven", "gradle":
+		return serviceJava
+	case "npm":
+		return serviceNode
+	}
+	return ""
+}
+
+// Java 获取 Java 配置，使用 config.LoadEnvironmentConfig 加载时已合并环境中的覆盖配置
+func (s *Service) Java() *config.JavaConfig {
+	return &s.Config.Java
+}
+
+// JarFile 当前版本中的 JAR 文件，构建产物为 WAR 时返回 WAR 文件
+func (s *Service) JarFile() string {
+	return javaArchive(s.Current, s.Config.Project.Name)
+}
+
+// javaArchive 版本目录中的 <项目>.war，不存在时为 <项目>.jar
+func javaArchive(dir, name string) string {
+	if war := filepath.Join(dir, name+".war"); utils.FileExists(war) {
+		return war
+	}
+	return filepath.Join(dir, name+".jar")
+}
+
+// LogFile 日志文件
+func (s *Service) LogFile() string {
+	return filepath.Join(s.DeployPath, "logs", s.Name+".log")
+}
+
+// PidFile PID 文件
+func (s *Service) PidFile() string {
+	return filepath.Join(s.DeployPath, s.Name+".pid")
+}
+
+// EnvFile 保存 scripts.variables 的环境变量文件，位于版本目录之外，只有所有者可以读写
+func (s *Service) EnvFile() string {
+	return filepath.Join(s.DeployPath, s.Name+".env")
+}
+
+// variableNames 按名称排序的 scripts.variables
+func (s *Service) variableNames() []string {
+	names := make([]string, 0, len(s.Env.Scripts.Variables))
+	for name := range s.Env.Scripts.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writePrivateFile 写入权限为 0600 的文件，data 为空时删除文件
+//
+// scripts.variables 中可能包含密钥，不能写入其他用户可读的 unit、ecosystem 文件。
+// 先写入同目录的临时文件 (os.CreateTemp 创建时权限即为 0600) 再重命名，
+// 替换已存在的文件时也不会出现其他用户可读的窗口。
+func writePrivateFile(path string, data []byte) error {
+	if len(data) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除环境变量文件失败: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("创建环境变量文件失败: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("写入环境变量文件失败: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("写入环境变量文件失败: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("写入环境变量文件失败: %w", err)
+	}
+	return nil
+}
+
+// NewServiceManager 根据 service.manager 创建服务管理器
+func NewServiceManager(service *Service) (ServiceManager, error) {
+	switch service.Env.Service.Manager {
+	case "", ManagerNohup:
+		return &nohupManager{service: service}, nil
+	case ManagerSystemd:
+		return newSystemdManager(service)
+	case ManagerPM2:
+		if service.Kind != serviceNode {
+			return nil, fmt.Errorf("pm2 只能管理 Node 服务")
+		}
+		return &pm2Manager{service: service}, nil
+	default:
+		return nil, fmt.Errorf("不支持的服务管理器: %s (可选 %s, %s, %s)", service.Env.Service.Manager, ManagerNohup, ManagerSystemd, ManagerPM2)
+	}
+}
+
+// serviceDeployer Java/Node 服务部署
+//
+// 构建产物解压到 <deploy_path>/releases/<版本号>，切换 <deploy_path>/current
+// 后由服务管理器重启服务。重启失败时切换回原来的版本并再次重启。
+type serviceDeployer struct {
+	config  *config.Config
+	env     *config.EnvironmentConfig
+	options *Options
+}
+
+// newServiceDeployer 创建服务部署
+func newServiceDeployer(cfg *config.Config, env *config.EnvironmentConfig, options *Options) *serviceDeployer {
+	return &serviceDeployer{
+		config:  cfg,
+		env:     env,
+		options: options,
+	}
+}
+
+// Deploy 执行部署
+func (d *serviceDeployer) Deploy() error {
+	if d.env.DeployPath == "" {
+		return fmt.Errorf("服务部署需要配置 deploy_path")
+	}
+
+	layout := &releaseLayout{
+		current:  filepath.Join(d.env.DeployPath, "current"),
+		releases: filepath.Join(d.env.DeployPath, "releases"),
+		keep:     d.config.Deploy.BackupCount,
+	}
+
+	previous, err := layout.active()
+	if err != nil {
+		return err
+	}
+
+	// 解压到版本目录
+	releaseDir, err := layout.extract(d.options.ArtifactPath, d.options.Version, d.config.Project.Name)
+	if err != nil {
+		return err
+	}
+
+	// 切换符号链接前创建服务管理器，配置错误时不影响正在运行的版本
+	service, err := NewService(d.config, d.options.Environment, d.options.Builder, d.options.Verbose)
+	if err != nil {
+		return err
+	}
+	service.Kind = service.detectKind(releaseDir, d.options.Builder)
+	manager, err := NewServiceManager(service)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("⚙️  服务 %s (%s, %s)\n", service.Name, service.Kind, manager.Name())
+
+	if err := os.MkdirAll(filepath.Join(d.env.DeployPath, "logs"), 0755); err != nil {
+		return fmt.Errorf("创建日志目录失败: %w", err)
+	}
+
+	// 切换符号链接
+	if err := layout.activate(releaseDir); err != nil {
+		return err
+	}
+
+	// 安装并重启服务
+	err = manager.Install()
+	if err == nil {
+		err = manager.Restart()
+	}
+	if err != nil {
+		utils.PrintError(err.Error())
+		if previous == "" {
+			return fmt.Errorf("部署失败: %w", err)
+		}
+
+		fmt.Println("⏪ 回滚...")
+		if rollbackErr := layout.activate(previous); rollbackErr != nil {
+			return fmt.Errorf("%v，且回滚失败: %w", err, rollbackErr)
+		}
+		if rollbackErr := manager.Restart(); rollbackErr != nil {
+			return fmt.Errorf("%v，且回滚后重启失败: %w", err, rollbackErr)
+		}
+		return fmt.Errorf("部署失败，已回滚: %w", err)
+	}
+
+	if status, err := manager.Status(); err == nil {
+		fmt.Printf("✓ 服务状态: %s\n", status)
+	}
+
+	if err := layout.prune(releaseDir, previous); err != nil {
+		utils.PrintWarning(fmt.Sprintf("清理旧版本失败: %v", err))
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("服务已部署: %s %s", service.Name, d.options.Version))
+	return nil
+}
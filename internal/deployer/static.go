@@ -0,0 +1,270 @@
+package deployer
+
+import (
+	"deploy/internal/config"
+	"deploy/internal/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// nginxServerTemplate 内置的 nginx server 配置，找不到文件时回退到 index.html 以支持前端路由
+const nginxServerTemplate = `# 由 deploy 生成: {{.Project}} {{.Version}} ({{.Environment}})
+server {
+    listen {{.Listen}};
+    server_name {{.ServerName}};
+
+    root {{.Root}};
+    index index.html;
+
+    location / {
+        try_files $uri $uri/ /index.html;
+    }
+}
+`
+
+// nginxTemplateData nginx 配置模板变量
+type nginxTemplateData struct {
+	Project     string
+	Version     string
+	Environment string
+	ServerName  string
+	Listen      int
+	Root        string
+	Variables   map[string]string
+}
+
+// staticDeployer 静态站点部署
+//
+// 构建产物解压到 <releases_dir>/<版本号>，然后原子地替换 web_root 符号链接。
+// 配置了 nginx 时生成 server 配置并执行 nginx -t，测试或重新加载失败时
+// 恢复原来的符号链接和配置。
+type staticDeployer struct {
+	config  *config.Config
+	env     *config.EnvironmentConfig
+	options *Options
+}
+
+// staticSnapshot 切换前的状态，用于回滚
+type staticSnapshot struct {
+	previousRelease string // 原符号链接指向的目录，为空表示之前没有部署
+	nginxConfig     []byte // 原 nginx 配置内容
+	nginxExisted    bool   // 原 nginx 配置是否存在
+}
+
+// newStaticDeployer 创建静态站点部署
+func newStaticDeployer(cfg *config.Config, env *config.EnvironmentConfig, options *Options) *staticDeployer {
+	return &staticDeployer{
+		config:  cfg,
+		env:     env,
+		options: options,
+	}
+}
+
+// Deploy 执行部署
+func (s *staticDeployer) Deploy() error {
+	if s.env.DeployPath == "" && (s.env.Static.WebRoot == "" || s.env.Static.ReleasesDir == "") {
+		return fmt.Errorf("静态站点部署需要配置 deploy_path，或同时配置 static.web_root 和 static.releases_dir")
+	}
+
+	layout := s.layout()
+	fmt.Printf("🌐 部署静态站点到 %s (%s)\n", layout.current, s.options.Environment)
+
+	snapshot, err := s.snapshot(layout)
+	if err != nil {
+		return err
+	}
+
+	// 解压到版本目录
+	releaseDir, err := layout.extract(s.options.ArtifactPath, s.options.Version, "")
+	if err != nil {
+		return err
+	}
+
+	// 先生成并测试 nginx 配置，通过后再切换符号链接和重新加载
+	if err := s.writeNginx(layout.current); err != nil {
+		return s.abort(layout, snapshot, err)
+	}
+	if err := layout.activate(releaseDir); err != nil {
+		return s.abort(layout, snapshot, err)
+	}
+	if err := s.reloadNginx(); err != nil {
+		return s.abort(layout, snapshot, err)
+	}
+
+	if err := layout.prune(releaseDir, snapshot.previousRelease); err != nil {
+		utils.PrintWarning(fmt.Sprintf("清理旧版本失败: %v", err))
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("静态站点已部署: %s", s.options.Version))
+	return nil
+}
+
+// layout 获取版本目录布局
+func (s *staticDeployer) layout() *releaseLayout {
+	layout := &releaseLayout{
+		current:  s.env.Static.WebRoot,
+		releases: s.env.Static.ReleasesDir,
+		keep:     s.env.Static.KeepReleases,
+	}
+	if layout.current == "" {
+		layout.current = filepath.Join(s.env.DeployPath, "current")
+	}
+	if layout.releases == "" {
+		layout.releases = filepath.Join(s.env.DeployPath, "releases")
+	}
+	if layout.keep == 0 {
+		layout.keep = s.config.Deploy.BackupCount
+	}
+	return layout
+}
+
+// snapshot 记录切换前的符号链接和 nginx 配置
+func (s *staticDeployer) snapshot(layout *releaseLayout) (*staticSnapshot, error) {
+	previous, err := layout.active()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &staticSnapshot{previousRelease: previous}
+
+	if path := s.env.Static.Nginx.ConfigPath; path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			snapshot.nginxConfig = data
+			snapshot.nginxExisted = true
+		case !os.IsNotExist(err):
+			return nil, fmt.Errorf("读取 nginx 配置失败: %w", err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// nginxEnabled 检查是否需要生成配置或重新加载 nginx
+func (s *staticDeployer) nginxEnabled() bool {
+	return s.env.Static.Nginx.ConfigPath != "" || s.env.Static.Nginx.Reload
+}
+
+// writeNginx 生成 nginx 配置并测试，此时 current 仍指向原版本
+func (s *staticDeployer) writeNginx(webRoot string) error {
+	nginx := s.env.Static.Nginx
+	if !s.nginxEnabled() {
+		return nil
+	}
+
+	if nginx.ConfigPath != "" {
+		content, err := s.renderNginxConfig(webRoot)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(nginx.ConfigPath), 0755); err != nil {
+			return fmt.Errorf("创建 nginx 配置目录失败: %w", err)
+		}
+		if err := os.WriteFile(nginx.ConfigPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("写入 nginx 配置失败: %w", err)
+		}
+		fmt.Printf("📝 已生成 nginx 配置: %s\n", nginx.ConfigPath)
+	}
+
+	testCommand := nginx.TestCommand
+	if testCommand == "" {
+		testCommand = "nginx -t"
+	}
+	if output, err := runShell(s.config, "", testCommand, s.options.Verbose); err != nil {
+		return fmt.Errorf("nginx 配置测试失败: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	fmt.Println("✓ nginx 配置测试通过")
+	return nil
+}
+
+// reloadNginx 切换符号链接后重新加载 nginx
+func (s *staticDeployer) reloadNginx() error {
+	if !s.nginxEnabled() {
+		return nil
+	}
+
+	reloadCommand := s.env.Static.Nginx.ReloadCommand
+	if reloadCommand == "" {
+		reloadCommand = "nginx -s reload"
+	}
+	if output, err := runShell(s.config, "", reloadCommand, s.options.Verbose); err != nil {
+		return fmt.Errorf("nginx 重新加载失败: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	fmt.Println("✓ nginx 已重新加载")
+	return nil
+}
+
+// renderNginxConfig 渲染 nginx server 配置
+func (s *staticDeployer) renderNginxConfig(webRoot string) (string, error) {
+	nginx := s.env.Static.Nginx
+
+	text := nginxServerTemplate
+	if nginx.Template != "" {
+		templatePath := nginx.Template
+		if !filepath.IsAbs(templatePath) {
+			templatePath = filepath.Join(s.options.ProjectPath, templatePath)
+		}
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return "", fmt.Errorf("读取 nginx 配置模板失败: %w", err)
+		}
+		text = string(data)
+	}
+
+	data := nginxTemplateData{
+		Project:     s.config.Project.Name,
+		Version:     s.options.Version,
+		Environment: s.options.Environment,
+		ServerName:  nginx.ServerName,
+		Listen:      nginx.Listen,
+		Root:        webRoot,
+		Variables:   s.env.Scripts.Variables,
+	}
+	if data.ServerName == "" {
+		data.ServerName = "_"
+	}
+	if data.Listen == 0 {
+		data.Listen = s.env.ServicePort
+	}
+	if data.Listen == 0 {
+		data.Listen = 80
+	}
+	if data.Variables == nil {
+		data.Variables = map[string]string{}
+	}
+
+	return config.RenderTemplate("nginx", text, data)
+}
+
+// abort 部署失败时回滚并返回错误
+func (s *staticDeployer) abort(layout *releaseLayout, snapshot *staticSnapshot, err error) error {
+	utils.PrintError(err.Error())
+	if rollbackErr := s.rollback(layout, snapshot); rollbackErr != nil {
+		return fmt.Errorf("%v，且回滚失败: %w", err, rollbackErr)
+	}
+	return fmt.Errorf("部署失败，已回滚: %w", err)
+}
+
+// rollback 恢复原来的符号链接和 nginx 配置
+func (s *staticDeployer) rollback(layout *releaseLayout, snapshot *staticSnapshot) error {
+	fmt.Println("⏪ 回滚...")
+
+	if err := layout.activate(snapshot.previousRelease); err != nil {
+		return err
+	}
+
+	if path := s.env.Static.Nginx.ConfigPath; path != "" {
+		if snapshot.nginxExisted {
+			if err := os.WriteFile(path, snapshot.nginxConfig, 0644); err != nil {
+				return fmt.Errorf("恢复 nginx 配置失败: %w", err)
+			}
+		} else if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("移除 nginx 配置失败: %w", err)
+		}
+		fmt.Printf("📝 已恢复 nginx 配置: %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package deployer
+
+import (
+	"deploy/internal/config"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// systemdUnitTemplate systemd 服务单元模板
+const systemdUnitTemplate = `# 由 deploy 生成，重新部署时会被覆盖
+[Unit]
+Description={{.Description}}
+After=network.target
+
+[Service]
+Type=simple
+{{- if .User}}
+User={{.User}}
+{{- end}}
+WorkingDirectory={{.WorkingDirectory}}
+ExecStart={{.ExecStart}}
+Restart={{.Restart}}
+RestartSec={{.RestartSec}}
+{{- if .EnvironmentFile}}
+EnvironmentFile={{.EnvironmentFile}}
+{{- end}}
+
+[Install]
+WantedBy={{.WantedBy}}
+`
+
+// systemdUnitData systemd 服务单元模板变量
+type systemdUnitData struct {
+	Description      string
+	User             string
+	WorkingDirectory string
+	ExecStart        string
+	Restart          string
+	RestartSec       int
+	EnvironmentFile  string // scripts.variables，unit 文件所有用户可读，不直接写入变量值
+	WantedBy         string
+}
+
+// systemdManager 使用 systemd 管理服务
+type systemdManager struct {
+	service *Service
+	user    bool // 用户范围 (systemctl --user)
+}
+
+// newSystemdManager 创建 systemd 服务管理器
+func newSystemdManager(service *Service) (*systemdManager, error) {
+	switch service.Env.Service.Systemd.Scope {
+	case "", "system":
+		return &systemdManager{service: service}, nil
+	case "user":
+		return &systemdManager{service: service, user: true}, nil
+	default:
+		return nil, fmt.Errorf("不支持的 systemd 范围: %s (可选 system, user)", service.Env.Service.Systemd.Scope)
+	}
+}
+
+// Name 管理器名称
+func (m *systemdManager) Name() string {
+	return ManagerSystemd
+}
+
+// unitName 服务单元名称
+func (m *systemdManager) unitName() string {
+	return m.service.Name + ".service"
+}
+
+// unitPath 服务单元文件路径
+func (m *systemdManager) unitPath() (string, error) {
+	dir := m.service.Env.Service.Systemd.UnitDir
+	if dir == "" {
+		if !m.user {
+			dir = "/etc/systemd/system"
+		} else {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("获取用户目录失败: %w", err)
+			}
+			dir = filepath.Join(home, ".config", "systemd", "user")
+		}
+	}
+	return filepath.Join(dir, m.unitName()), nil
+}
+
+// Install 写入环境变量文件和服务单元文件，重新加载 systemd 并设置开机启动
+func (m *systemdManager) Install() error {
+	unit, err := m.renderUnit()
+	if err != nil {
+		return err
+	}
+	if err := writePrivateFile(m.service.EnvFile(), m.renderEnvironmentFile()); err != nil {
+		return err
+	}
+
+	path, err := m.unitPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建 unit 目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+		return fmt.Errorf("写入 unit 文件失败: %w", err)
+	}
+	fmt.Printf("📝 已生成 systemd unit: %s\n", path)
+
+	if err := m.systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	return m.systemctl("enable", m.unitName())
+}
+
+// Start 启动服务
+func (m *systemdManager) Start() error {
+	fmt.Printf("▶️  启动 %s...\n", m.unitName())
+	return m.systemctl("start", m.unitName())
+}
+
+// Stop 停止服务
+func (m *systemdManager) Stop() error {
+	fmt.Printf("⏹️  停止 %s...\n", m.unitName())
+	return m.systemctl("stop", m.unitName())
+}
+
+// Restart 重启服务
+func (m *systemdManager) Restart() error {
+	fmt.Printf("🔄 重启 %s...\n", m.unitName())
+	return m.systemctl("restart", m.unitName())
+}
+
+// Status 使用 systemctl is-active 获取服务状态
+func (m *systemdManager) Status() (string, error) {
+	// 服务未运行时 is-active 的退出码不为 0，但仍会输出状态
+	output, err := exec.Command("systemctl", m.systemctlArgs("is-active", m.unitName())...).Output()
+	status := strings.TrimSpace(string(output))
+	if status == "" && err != nil {
+		return "", fmt.Errorf("获取服务状态失败: %w", err)
+	}
+	return status, nil
+}
+
+// renderUnit 渲染服务单元文件
+func (m *systemdManager) renderUnit() (string, error) {
+	systemd := m.service.Env.Service.Systemd
+
+	execStart := systemd.ExecStart
+	if execStart == "" {
+		var err error
+		if execStart, err = m.defaultExecStart(); err != nil {
+			return "", err
+		}
+	}
+
+	data := systemdUnitData{
+		Description:      fmt.Sprintf("%s (deploy)", m.service.Name),
+		WorkingDirectory: m.service.Current,
+		ExecStart:        execStart,
+		Restart:          systemd.Restart,
+		RestartSec:       m.service.Config.Deploy.RestartDelay,
+		WantedBy:         "multi-user.target",
+	}
+	if !m.user {
+		data.User = systemd.User
+	} else {
+		data.WantedBy = "default.target"
+	}
+	if data.Restart == "" {
+		data.Restart = "on-failure"
+	}
+	if data.RestartSec == 0 {
+		data.RestartSec = 5
+	}
+
+	// 环境变量来自 scripts.variables，保存在权限为 0600 的文件中
+	if len(m.service.Env.Scripts.Variables) > 0 {
+		data.EnvironmentFile = m.service.EnvFile()
+	}
+
+	return config.RenderTemplate("systemd", systemdUnitTemplate, data)
+}
+
+// environmentFileEscaper 转义 EnvironmentFile 双引号中有特殊含义的字符
+var environmentFileEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`)
+
+// renderEnvironmentFile 生成 EnvironmentFile，每行为 NAME="value"
+func (m *systemdManager) renderEnvironmentFile() []byte {
+	var buf strings.Builder
+	for _, name := range m.service.variableNames() {
+		fmt.Fprintf(&buf, "%s=\"%s\"\n", name, environmentFileEscaper.Replace(m.service.Env.Scripts.Variables[name]))
+	}
+	return []byte(buf.String())
+}
+
+// defaultExecStart 根据服务类型生成启动命令
+func (m *systemdManager) defaultExecStart() (string, error) {
+	switch m.service.Kind {
+	case serviceJava:
+		runtime := m.service.Java().Runtime
+		args := []string{"/usr/bin/env", "java"}
+		if runtime.HeapSize.Min != "" {
+			args = append(args, "-Xms"+runtime.HeapSize.Min)
+		}
+		if runtime.HeapSize.Max != "" {
+			args = append(args, "-Xmx"+runtime.HeapSize.Max)
+		}
+		args = append(args, runtime.JvmOptions...)
+		args = append(args, "-jar", m.service.JarFile())
+		args = append(args, runtime.AppOptions...)
+		return strings.Join(args, " "), nil
+	case serviceNode:
+		return "/usr/bin/env npm start", nil
+	default:
+		return "", fmt.Errorf("无法判断服务类型，请配置 service.systemd.exec_start")
+	}
+}
+
+// systemctlArgs 生成 systemctl 参数，用户范围时添加 --user
+func (m *systemdManager) systemctlArgs(args ...string) []string {
+	if m.user {
+		return append([]string{"--user"}, args...)
+	}
+	return args
+}
+
+// systemctl 执行 systemctl 命令
+func (m *systemdManager) systemctl(args ...string) error {
+	args = m.systemctlArgs(args...)
+	if err := runCommand(m.service.Verbose, "systemctl", args...); err != nil {
+		return fmt.Errorf("systemctl %s 失败: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
@@ -13,16 +13,22 @@ const (
 	ProjectTypeNPM     ProjectType = "npm"
 	ProjectTypeMaven   ProjectType = "maven"
 	ProjectTypeGradle  ProjectType = "gradle"
+	ProjectTypeGo      ProjectType = "go"
+	ProjectTypePython  ProjectType = "python"
+	ProjectTypeDocker  ProjectType = "docker"
 	ProjectTypeUnknown ProjectType = "unknown"
 )
 
 // ProjectInfo 项目信息
 type ProjectInfo struct {
-	Type         ProjectType
-	Name         string
-	Version      string
-	BuildCommand string
-	ArtifactPath string
+	Type          ProjectType
+	Name          string
+	Version       string
+	BuildCommand  string
+	ArtifactPath  string
+	HasDockerfile bool               // 项目目录中存在 Dockerfile，可以使用 docker 构建器
+	Go            *GoModuleInfo      // Go 项目的模块信息
+	Python        *PythonProjectInfo // Python 项目信息
 }
 
 // DetectProject 检测项目类型
@@ -31,18 +37,40 @@ func DetectProject(projectPath string) (*ProjectInfo, error) {
 		projectPath = "."
 	}
 
+	hasDockerfile := IsDockerProject(projectPath)
+
 	// 检测 NPM 项目
 	if info, err := detectNPMProject(projectPath); err == nil {
+		info.HasDockerfile = hasDockerfile
 		return info, nil
 	}
 
 	// 检测 Maven 项目
 	if info, err := detectMavenProject(projectPath); err == nil {
+		info.HasDockerfile = hasDockerfile
 		return info, nil
 	}
 
 	// 检测 Gradle 项目
 	if info, err := detectGradleProject(projectPath); err == nil {
+		info.HasDockerfile = hasDockerfile
+		return info, nil
+	}
+
+	// 检测 Go 项目
+	if info, err := detectGoProject(projectPath); err == nil {
+		info.HasDockerfile = hasDockerfile
+		return info, nil
+	}
+
+	// 检测 Python 项目
+	if info, err := detectPythonProject(projectPath); err == nil {
+		info.HasDockerfile = hasDockerfile
+		return info, nil
+	}
+
+	// 只有 Dockerfile 的项目使用 docker 构建器
+	if info, err := detectDockerProject(projectPath); err == nil {
 		return info, nil
 	}
 
@@ -105,6 +133,57 @@ func detectGradleProject(projectPath string) (*ProjectInfo, error) {
 	}, nil
 }
 
+// detectDockerProject 检测 Docker 项目
+func detectDockerProject(projectPath string) (*ProjectInfo, error) {
+	if !IsDockerProject(projectPath) {
+		return nil, fmt.Errorf("Dockerfile 不存在")
+	}
+
+	return &ProjectInfo{
+		Type:          ProjectTypeDocker,
+		Name:          filepath.Base(projectPath),
+		BuildCommand:  "docker build",
+		HasDockerfile: true,
+	}, nil
+}
+
+// detectGoProject 检测 Go 项目
+func detectGoProject(projectPath string) (*ProjectInfo, error) {
+	goModule, err := ParseGoModule(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectInfo{
+		Type:         ProjectTypeGo,
+		Name:         filepath.Base(projectPath),
+		BuildCommand: "go build -trimpath",
+		ArtifactPath: "bin/",
+		Go:           goModule,
+	}, nil
+}
+
+// detectPythonProject 检测 Python 项目
+func detectPythonProject(projectPath string) (*ProjectInfo, error) {
+	pythonInfo, ok := ParsePythonProject(projectPath)
+	if !ok {
+		return nil, fmt.Errorf("pyproject.toml、setup.py 或 requirements.txt 不存在")
+	}
+
+	name := pythonInfo.Name
+	if name == "" {
+		name = filepath.Base(projectPath)
+	}
+
+	return &ProjectInfo{
+		Type:         ProjectTypePython,
+		Name:         name,
+		Version:      pythonInfo.Version,
+		BuildCommand: "pip install --target vendor",
+		Python:       pythonInfo,
+	}, nil
+}
+
 // IsNPMProject 检查是否为 NPM 项目
 func IsNPMProject(projectPath string) bool {
 	packageJsonPath := filepath.Join(projectPath, "package.json")
@@ -129,3 +208,21 @@ func IsGradleProject(projectPath string) bool {
 
 	return err1 == nil || err2 == nil
 }
+
+// IsGoProject 检查是否为 Go 项目
+func IsGoProject(projectPath string) bool {
+	_, err := os.Stat(filepath.Join(projectPath, "go.mod"))
+	return err == nil
+}
+
+// IsPythonProject 检查是否为 Python 项目
+func IsPythonProject(projectPath string) bool {
+	_, ok := ParsePythonProject(projectPath)
+	return ok
+}
+
+// IsDockerProject 检查项目目录中是否存在 Dockerfile
+func IsDockerProject(projectPath string) bool {
+	_, err := os.Stat(filepath.Join(projectPath, "Dockerfile"))
+	return err == nil
+}
@@ -0,0 +1,101 @@
+package detector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GoModuleInfo Go 模块信息
+type GoModuleInfo struct {
+	Module       string   // 模块路径
+	GoVersion    string   // go.mod 中的 go 版本
+	MainPackages []string // main 包，相对项目目录，例如 . 或 ./cmd/server
+}
+
+// ParseGoModule 解析 go.mod 并查找项目根目录和 cmd/ 下的 main 包
+func ParseGoModule(projectPath string) (*GoModuleInfo, error) {
+	file, err := os.Open(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("go.mod 不存在")
+	}
+	defer file.Close()
+
+	info := &GoModuleInfo{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			info.Module = strings.Trim(fields[1], `"`)
+		case "go":
+			info.GoVersion = fields[1]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 go.mod 失败: %w", err)
+	}
+	if info.Module == "" {
+		return nil, fmt.Errorf("go.mod 中缺少 module 声明")
+	}
+
+	if isMainPackage(projectPath) {
+		info.MainPackages = append(info.MainPackages, ".")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(projectPath, "cmd"))
+	if err == nil {
+		var packages []string
+		for _, entry := range entries {
+			if entry.IsDir() && isMainPackage(filepath.Join(projectPath, "cmd", entry.Name())) {
+				packages = append(packages, "./cmd/"+entry.Name())
+			}
+		}
+		sort.Strings(packages)
+		info.MainPackages = append(info.MainPackages, packages...)
+	}
+
+	return info, nil
+}
+
+// isMainPackage 检查目录中是否有 package main 的 Go 文件
+func isMainPackage(dir string) bool {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return false
+	}
+
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		if goPackageName(path) == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// goPackageName 读取 Go 文件的包名
+func goPackageName(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "package" {
+			return fields[1]
+		}
+	}
+	return ""
+}
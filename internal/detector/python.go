@@ -0,0 +1,67 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// PythonProjectInfo Python 项目信息
+type PythonProjectInfo struct {
+	Name            string
+	Version         string
+	RequiresPython  string // pyproject.toml 中的 requires-python
+	HasPyproject    bool
+	HasSetupPy      bool
+	HasRequirements bool
+}
+
+// ParsePythonProject 检测 pyproject.toml、setup.py 和 requirements.txt
+func ParsePythonProject(projectPath string) (*PythonProjectInfo, bool) {
+	info := &PythonProjectInfo{
+		HasPyproject:    fileExists(filepath.Join(projectPath, "pyproject.toml")),
+		HasSetupPy:      fileExists(filepath.Join(projectPath, "setup.py")),
+		HasRequirements: fileExists(filepath.Join(projectPath, "requirements.txt")),
+	}
+	if !info.HasPyproject && !info.HasSetupPy && !info.HasRequirements {
+		return nil, false
+	}
+
+	// 解析失败时仍视为 Python 项目，只是缺少名称和版本信息
+	if info.HasPyproject {
+		data, err := os.ReadFile(filepath.Join(projectPath, "pyproject.toml"))
+		if err == nil {
+			var pyproject struct {
+				Project struct {
+					Name           string `toml:"name"`
+					Version        string `toml:"version"`
+					RequiresPython string `toml:"requires-python"`
+				} `toml:"project"`
+				Tool struct {
+					Poetry struct {
+						Name    string `toml:"name"`
+						Version string `toml:"version"`
+					} `toml:"poetry"`
+				} `toml:"tool"`
+			}
+			if toml.Unmarshal(data, &pyproject) == nil {
+				info.Name = pyproject.Project.Name
+				info.Version = pyproject.Project.Version
+				info.RequiresPython = pyproject.Project.RequiresPython
+				if info.Name == "" {
+					info.Name = pyproject.Tool.Poetry.Name
+					info.Version = pyproject.Tool.Poetry.Version
+				}
+			}
+		}
+	}
+
+	return info, true
+}
+
+// fileExists 检查文件是否存在
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
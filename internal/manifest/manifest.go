@@ -0,0 +1,202 @@
+package manifest
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"deploy/internal/utils"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Manifest 构建清单
+type Manifest struct {
+	Project      string            `json:"project"`
+	Version      string            `json:"version"`
+	Builder      string            `json:"builder"`
+	Git          *utils.GitInfo    `json:"git,omitempty"`
+	Toolchain    map[string]string `json:"toolchain,omitempty"`
+	BuildCommand string            `json:"build_command"`
+	Timestamp    string            `json:"timestamp"`
+	Host         string            `json:"host"`
+	Artifact     FileChecksum      `json:"artifact"`
+	Files        []FileChecksum    `json:"files,omitempty"`
+}
+
+// FileChecksum 文件校验信息
+type FileChecksum struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestPath 返回构建产物对应的清单文件路径
+func ManifestPath(artifactPath string) string {
+	return artifactPath + ".manifest.json"
+}
+
+// ChecksumPath 返回构建产物对应的校验文件路径
+func ChecksumPath(artifactPath string) string {
+	return artifactPath + ".sha256"
+}
+
+// Save 保存清单到文件
+func Save(m *Manifest, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建清单文件失败: %w", err)
+	}
+	defer file.Close()
+
+	// 构建命令中常含有 &&，不做 HTML 转义
+	encoder := json.NewEncoder(file)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(m); err != nil {
+		return fmt.Errorf("写入清单文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// Load 从文件加载清单
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取清单文件失败: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("解析清单文件失败: %w", err)
+	}
+
+	return &m, nil
+}
+
+// FileSHA256 计算文件的 SHA-256 校验和
+func FileSHA256(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// WriteChecksumFile 写入 sha256sum 格式的校验文件
+func WriteChecksumFile(artifactPath, checksum string) error {
+	line := fmt.Sprintf("%s  %s\n", checksum, filepath.Base(artifactPath))
+	if err := os.WriteFile(ChecksumPath(artifactPath), []byte(line), 0644); err != nil {
+		return fmt.Errorf("写入校验文件失败: %w", err)
+	}
+	return nil
+}
+
+// ReadChecksumFile 读取校验文件中的 SHA-256 值
+func ReadChecksumFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("读取校验文件失败: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+
+	return "", fmt.Errorf("校验文件格式无效: %s", path)
+}
+
+// ChecksumLines 生成 sha256sum 格式的多行校验内容，按路径排序
+func ChecksumLines(files []FileChecksum) string {
+	sorted := make([]FileChecksum, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	var builder strings.Builder
+	for _, f := range sorted {
+		fmt.Fprintf(&builder, "%s  %s\n", f.SHA256, f.Path)
+	}
+	return builder.String()
+}
+
+// DirChecksum 计算目录产物的校验和，即目录内所有文件校验行的 SHA-256
+func DirChecksum(files []FileChecksum) string {
+	sum := sha256.Sum256([]byte(ChecksumLines(files)))
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteDirChecksumFile 为目录产物写入校验文件，每行对应目录内的一个文件
+func WriteDirChecksumFile(dirPath string, files []FileChecksum) error {
+	if err := os.WriteFile(ChecksumPath(dirPath), []byte(ChecksumLines(files)), 0644); err != nil {
+		return fmt.Errorf("写入校验文件失败: %w", err)
+	}
+	return nil
+}
+
+// VerifyChecksum 校验构建产物与 .sha256 文件是否一致
+func VerifyChecksum(artifactPath string) error {
+	if info, err := os.Stat(artifactPath); err == nil && info.IsDir() {
+		return verifyDirChecksum(artifactPath)
+	}
+
+	expected, err := ReadChecksumFile(ChecksumPath(artifactPath))
+	if err != nil {
+		return err
+	}
+
+	actual, _, err := FileSHA256(artifactPath)
+	if err != nil {
+		return fmt.Errorf("计算校验和失败: %w", err)
+	}
+
+	if actual != expected {
+		return fmt.Errorf("校验和不匹配: 期望 %s，实际 %s", expected, actual)
+	}
+
+	return nil
+}
+
+// verifyDirChecksum 校验目录产物内的每个文件
+func verifyDirChecksum(dirPath string) error {
+	data, err := os.ReadFile(ChecksumPath(dirPath))
+	if err != nil {
+		return fmt.Errorf("读取校验文件失败: %w", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.SplitN(line, "  ", 2)
+		if len(fields) != 2 {
+			return fmt.Errorf("校验文件格式无效: %s", line)
+		}
+
+		actual, _, err := FileSHA256(filepath.Join(dirPath, filepath.FromSlash(fields[1])))
+		if err != nil {
+			return fmt.Errorf("计算 %s 校验和失败: %w", fields[1], err)
+		}
+		if actual != strings.ToLower(fields[0]) {
+			return fmt.Errorf("%s 校验和不匹配: 期望 %s，实际 %s", fields[1], fields[0], actual)
+		}
+	}
+
+	return nil
+}
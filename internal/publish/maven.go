@@ -0,0 +1,229 @@
+package publish
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"deploy/internal/config"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// MavenArtifact 待发布的 Maven 构件
+type MavenArtifact struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Packaging  string // jar、tar.gz、zip 等，作为文件扩展名
+	File       string
+}
+
+// MavenPublisher Maven 仓库发布器，使用 Nexus/Artifactory 通用的目录结构
+type MavenPublisher struct {
+	config config.MavenPublishConfig
+}
+
+// NewMavenPublisher 创建 Maven 发布器
+func NewMavenPublisher(cfg config.MavenPublishConfig) (*MavenPublisher, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("未配置 publish.maven.url")
+	}
+	if cfg.GroupID == "" {
+		return nil, fmt.Errorf("未配置 publish.maven.group_id")
+	}
+	return &MavenPublisher{config: cfg}, nil
+}
+
+// Publish 上传构件、POM 及其校验文件，并更新 maven-metadata.xml
+func (p *MavenPublisher) Publish(artifact MavenArtifact) ([]string, error) {
+	data, err := os.ReadFile(artifact.File)
+	if err != nil {
+		return nil, fmt.Errorf("读取构建产物失败: %w", err)
+	}
+
+	baseName := fmt.Sprintf("%s-%s", artifact.ArtifactID, artifact.Version)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{baseName + "." + artifact.Packaging, data},
+		{baseName + ".pom", GeneratePOM(artifact)},
+	}
+
+	var uploaded []string
+	for _, f := range files {
+		urls, err := p.uploadWithChecksums(p.versionPath(artifact, f.name), f.data)
+		if err != nil {
+			return uploaded, err
+		}
+		uploaded = append(uploaded, urls...)
+	}
+
+	metadataURLs, err := p.updateMetadata(artifact)
+	if err != nil {
+		return uploaded, err
+	}
+
+	return append(uploaded, metadataURLs...), nil
+}
+
+// artifactPath 构件目录路径：<groupId 以 / 分隔>/<artifactId>
+func (p *MavenPublisher) artifactPath(artifact MavenArtifact) string {
+	return path.Join(strings.ReplaceAll(artifact.GroupID, ".", "/"), artifact.ArtifactID)
+}
+
+// versionPath 版本目录下文件的路径
+func (p *MavenPublisher) versionPath(artifact MavenArtifact, name string) string {
+	return path.Join(p.artifactPath(artifact), artifact.Version, name)
+}
+
+// url 拼接仓库地址
+func (p *MavenPublisher) url(relPath string) string {
+	return strings.TrimSuffix(p.config.URL, "/") + "/" + relPath
+}
+
+// mavenChecksums 随文件一起上传的校验文件
+var mavenChecksums = []struct {
+	ext     string
+	newHash func() hash.Hash
+}{
+	{"md5", md5.New},
+	{"sha1", sha1.New},
+	{"sha256", sha256.New},
+}
+
+// uploadWithChecksums 上传文件及其 .md5、.sha1、.sha256 校验文件
+func (p *MavenPublisher) uploadWithChecksums(relPath string, data []byte) ([]string, error) {
+	if err := p.put(relPath, data); err != nil {
+		return nil, err
+	}
+	uploaded := []string{p.url(relPath)}
+
+	for _, checksum := range mavenChecksums {
+		h := checksum.newHash()
+		h.Write(data)
+
+		name := relPath + "." + checksum.ext
+		if err := p.put(name, []byte(hex.EncodeToString(h.Sum(nil)))); err != nil {
+			return uploaded, err
+		}
+		uploaded = append(uploaded, p.url(name))
+	}
+
+	return uploaded, nil
+}
+
+// put 上传单个文件
+func (p *MavenPublisher) put(relPath string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, p.url(relPath), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	p.authorize(req)
+
+	_, err = request(req)
+	return err
+}
+
+// authorize 设置认证信息
+func (p *MavenPublisher) authorize(req *http.Request) {
+	if p.config.Username != "" {
+		req.SetBasicAuth(p.config.Username, p.config.Password)
+	}
+}
+
+// mavenMetadata maven-metadata.xml 结构
+type mavenMetadata struct {
+	XMLName    xml.Name `xml:"metadata"`
+	GroupID    string   `xml:"groupId"`
+	ArtifactID string   `xml:"artifactId"`
+	Versioning struct {
+		Latest      string   `xml:"latest"`
+		Release     string   `xml:"release"`
+		Versions    []string `xml:"versions>version"`
+		LastUpdated string   `xml:"lastUpdated"`
+	} `xml:"versioning"`
+}
+
+// updateMetadata 读取已有的 maven-metadata.xml，追加版本后重新上传
+func (p *MavenPublisher) updateMetadata(artifact MavenArtifact) ([]string, error) {
+	relPath := path.Join(p.artifactPath(artifact), "maven-metadata.xml")
+
+	var metadata mavenMetadata
+	req, err := http.NewRequest(http.MethodGet, p.url(relPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	p.authorize(req)
+
+	body, err := request(req)
+	var statusErr *StatusError
+	switch {
+	case err == nil:
+		if err := xml.Unmarshal(body, &metadata); err != nil {
+			return nil, fmt.Errorf("解析 maven-metadata.xml 失败: %w", err)
+		}
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound:
+		// 首次发布
+	default:
+		return nil, err
+	}
+
+	metadata.GroupID = artifact.GroupID
+	metadata.ArtifactID = artifact.ArtifactID
+	if !containsString(metadata.Versioning.Versions, artifact.Version) {
+		metadata.Versioning.Versions = append(metadata.Versioning.Versions, artifact.Version)
+	}
+	metadata.Versioning.Latest = artifact.Version
+	if !strings.HasSuffix(artifact.Version, "-SNAPSHOT") {
+		metadata.Versioning.Release = artifact.Version
+	}
+	metadata.Versioning.LastUpdated = time.Now().UTC().Format("20060102150405")
+
+	data, err := xml.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成 maven-metadata.xml 失败: %w", err)
+	}
+
+	return p.uploadWithChecksums(relPath, append([]byte(xml.Header), append(data, '\n')...))
+}
+
+// GeneratePOM 生成最小的 POM 文件
+func GeneratePOM(artifact MavenArtifact) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<project xmlns="http://maven.apache.org/POM/4.0.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 http://maven.apache.org/xsd/maven-4.0.0.xsd">` + "\n")
+	buf.WriteString("  <modelVersion>4.0.0</modelVersion>\n")
+	for _, field := range []struct{ name, value string }{
+		{"groupId", artifact.GroupID},
+		{"artifactId", artifact.ArtifactID},
+		{"version", artifact.Version},
+		{"packaging", artifact.Packaging},
+	} {
+		buf.WriteString("  <" + field.name + ">")
+		xml.EscapeText(&buf, []byte(field.value))
+		buf.WriteString("</" + field.name + ">\n")
+	}
+	buf.WriteString("</project>\n")
+	return buf.Bytes()
+}
+
+// containsString 检查切片中是否包含指定字符串
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
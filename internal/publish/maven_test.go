@@ -0,0 +1,201 @@
+package publish
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"deploy/internal/config"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeMavenRepository 模拟 Nexus/Artifactory 的 Maven 仓库，PUT 保存文件，GET 返回已保存的文件
+type fakeMavenRepository struct {
+	mu       sync.Mutex
+	files    map[string][]byte
+	auth     []string // 每个请求的 Authorization 头
+	failPath string   // 对该路径的 PUT 返回 401
+}
+
+func newFakeMavenRepository(t *testing.T) (*fakeMavenRepository, *httptest.Server) {
+	repo := &fakeMavenRepository{files: make(map[string][]byte)}
+	server := httptest.NewServer(http.HandlerFunc(repo.ServeHTTP))
+	t.Cleanup(server.Close)
+	return repo, server
+}
+
+func (r *fakeMavenRepository) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.auth = append(r.auth, req.Header.Get("Authorization"))
+	relPath := strings.TrimPrefix(req.URL.Path, "/repository/releases/")
+
+	switch req.Method {
+	case http.MethodPut:
+		if relPath == r.failPath {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		data, _ := io.ReadAll(req.Body)
+		r.files[relPath] = data
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		data, ok := r.files[relPath]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write(data)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// writeArtifact 创建待发布的构建产物
+func writeArtifact(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.tar.gz")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMavenPublishLayout(t *testing.T) {
+	repo, server := newFakeMavenRepository(t)
+	publisher, err := NewMavenPublisher(config.MavenPublishConfig{
+		URL:      server.URL + "/repository/releases/",
+		Username: "deployer",
+		Password: "s3cret",
+		GroupID:  "com.example",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifact := MavenArtifact{
+		GroupID:    "com.example",
+		ArtifactID: "app",
+		Version:    "1.0.0",
+		Packaging:  "tar.gz",
+		File:       writeArtifact(t, "artifact content"),
+	}
+	uploaded, err := publisher.Publish(artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := "com/example/app/1.0.0/app-1.0.0"
+	expected := []string{
+		base + ".tar.gz", base + ".tar.gz.md5", base + ".tar.gz.sha1", base + ".tar.gz.sha256",
+		base + ".pom", base + ".pom.md5", base + ".pom.sha1", base + ".pom.sha256",
+		"com/example/app/maven-metadata.xml", "com/example/app/maven-metadata.xml.sha1",
+	}
+	for _, name := range expected {
+		if _, ok := repo.files[name]; !ok {
+			t.Errorf("未上传 %s", name)
+		}
+	}
+	if len(uploaded) != 12 {
+		t.Errorf("上传文件数为 %d，应为 12: %v", len(uploaded), uploaded)
+	}
+
+	// 校验文件内容为十六进制摘要
+	for _, name := range []string{base + ".tar.gz", base + ".pom"} {
+		data := repo.files[name]
+		sha1Sum := sha1.Sum(data)
+		md5Sum := md5.Sum(data)
+		if got := string(repo.files[name+".sha1"]); got != hex.EncodeToString(sha1Sum[:]) {
+			t.Errorf("%s.sha1 = %s", name, got)
+		}
+		if got := string(repo.files[name+".md5"]); got != hex.EncodeToString(md5Sum[:]) {
+			t.Errorf("%s.md5 = %s", name, got)
+		}
+	}
+	if string(repo.files[base+".tar.gz"]) != "artifact content" {
+		t.Errorf("构件内容不正确: %q", repo.files[base+".tar.gz"])
+	}
+
+	pom := string(repo.files[base+".pom"])
+	for _, want := range []string{"<groupId>com.example</groupId>", "<artifactId>app</artifactId>", "<version>1.0.0</version>", "<packaging>tar.gz</packaging>"} {
+		if !strings.Contains(pom, want) {
+			t.Errorf("POM 缺少 %s:\n%s", want, pom)
+		}
+	}
+
+	// 所有请求都带有 Basic 认证
+	for _, auth := range repo.auth {
+		if auth != "Basic ZGVwbG95ZXI6czNjcmV0" {
+			t.Fatalf("Authorization = %q", auth)
+		}
+	}
+}
+
+func TestMavenPublishMetadataMerge(t *testing.T) {
+	repo, server := newFakeMavenRepository(t)
+	publisher, err := NewMavenPublisher(config.MavenPublishConfig{URL: server.URL + "/repository/releases", GroupID: "com.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, version := range []string{"1.0.0", "1.1.0-SNAPSHOT"} {
+		artifact := MavenArtifact{GroupID: "com.example", ArtifactID: "app", Version: version, Packaging: "jar", File: writeArtifact(t, version)}
+		if _, err := publisher.Publish(artifact); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var metadata mavenMetadata
+	if err := xml.Unmarshal(repo.files["com/example/app/maven-metadata.xml"], &metadata); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(metadata.Versioning.Versions, ","); got != "1.0.0,1.1.0-SNAPSHOT" {
+		t.Errorf("versions = %s", got)
+	}
+	if metadata.Versioning.Latest != "1.1.0-SNAPSHOT" || metadata.Versioning.Release != "1.0.0" {
+		t.Errorf("latest = %s, release = %s", metadata.Versioning.Latest, metadata.Versioning.Release)
+	}
+
+	// 未配置用户名时不发送认证信息
+	for _, auth := range repo.auth {
+		if auth != "" {
+			t.Fatalf("Authorization = %q", auth)
+		}
+	}
+}
+
+func TestMavenPublishStatusError(t *testing.T) {
+	repo, server := newFakeMavenRepository(t)
+	repo.failPath = "com/example/app/1.0.0/app-1.0.0.pom"
+	publisher, err := NewMavenPublisher(config.MavenPublishConfig{URL: server.URL + "/repository/releases", GroupID: "com.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifact := MavenArtifact{GroupID: "com.example", ArtifactID: "app", Version: "1.0.0", Packaging: "jar", File: writeArtifact(t, "jar")}
+	uploaded, err := publisher.Publish(artifact)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("应返回 401 StatusError，实际为 %v", err)
+	}
+	if !strings.Contains(statusErr.Body, "unauthorized") {
+		t.Errorf("错误中应包含响应内容: %v", err)
+	}
+	// 失败之前上传的 JAR 及其校验文件仍然返回，元数据不更新
+	if len(uploaded) != 4 {
+		t.Errorf("已上传文件数为 %d，应为 4", len(uploaded))
+	}
+	if _, ok := repo.files["com/example/app/maven-metadata.xml"]; ok {
+		t.Error("上传失败时不应更新 maven-metadata.xml")
+	}
+}
@@ -0,0 +1,279 @@
+package publish
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha512"
+	"deploy/internal/config"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// 发布模式
+const (
+	NPMModePackage  = "package"  // 发布 npm pack 生成的包
+	NPMModeArtifact = "artifact" // 发布构建产物
+)
+
+// defaultNPMRegistry 默认 npm 仓库
+const defaultNPMRegistry = "https://registry.npmjs.org"
+
+// NPMPublisher npm 仓库发布器，直接调用仓库的发布接口
+type NPMPublisher struct {
+	config config.NPMPublishConfig
+}
+
+// NewNPMPublisher 创建 npm 发布器
+func NewNPMPublisher(cfg config.NPMPublishConfig) (*NPMPublisher, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("未配置 publish.npm.token")
+	}
+	if cfg.Registry == "" {
+		cfg.Registry = defaultNPMRegistry
+	}
+	if cfg.Tag == "" {
+		cfg.Tag = "latest"
+	}
+	return &NPMPublisher{config: cfg}, nil
+}
+
+// PackProject 在项目目录执行 npm pack，返回生成的 .tgz 路径
+func PackProject(projectPath string) (string, error) {
+	cmd := exec.Command("npm", "pack")
+	cmd.Dir = projectPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("执行 npm pack 失败: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	name := strings.TrimSpace(lines[len(lines)-1])
+	if !strings.HasSuffix(name, ".tgz") {
+		return "", fmt.Errorf("无法识别 npm pack 的输出: %s", name)
+	}
+
+	return filepath.Join(projectPath, name), nil
+}
+
+// PackArtifact 将构建产物重新打包为 npm 包格式（所有文件位于 package/ 下），
+// 构建产物中没有 package.json 时使用项目的 package.json
+func PackArtifact(artifactPath, packageJSONPath, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("创建 npm 包失败: %w", err)
+	}
+	defer out.Close()
+
+	gzWriter := gzip.NewWriter(out)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	hasPackageJSON := false
+	add := func(header *tar.Header, r io.Reader) error {
+		if header.Name == "package.json" {
+			hasPackageJSON = true
+		}
+		header.Name = "package/" + header.Name
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if r != nil {
+			_, err := io.Copy(tarWriter, r)
+			return err
+		}
+		return nil
+	}
+
+	info, err := os.Stat(artifactPath)
+	if err != nil {
+		return fmt.Errorf("读取构建产物失败: %w", err)
+	}
+	if info.IsDir() {
+		err = walkDirArtifact(artifactPath, add)
+	} else if strings.HasSuffix(artifactPath, ".tar.gz") || strings.HasSuffix(artifactPath, ".tgz") {
+		err = walkTarGz(artifactPath, add)
+	} else {
+		return fmt.Errorf("artifact 模式只支持 tar.gz 和 dir 格式的构建产物: %s", filepath.Base(artifactPath))
+	}
+	if err != nil {
+		return fmt.Errorf("重新打包构建产物失败: %w", err)
+	}
+
+	if !hasPackageJSON {
+		data, err := os.ReadFile(packageJSONPath)
+		if err != nil {
+			return fmt.Errorf("读取 package.json 失败: %w", err)
+		}
+		header := &tar.Header{Name: "package.json", Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := add(header, bytes.NewReader(data)); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzWriter.Close()
+}
+
+// walkTarGz 遍历 tar.gz 中的条目
+func walkTarGz(path string, fn func(*tar.Header, io.Reader) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		header.Name = strings.TrimPrefix(header.Name, "./")
+		if err := fn(header, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// walkDirArtifact 遍历目录格式的构建产物
+func walkDirArtifact(root string, fn func(*tar.Header, io.Reader) error) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+
+		if !info.Mode().IsRegular() {
+			return fn(header, nil)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return fn(header, file)
+	})
+}
+
+// readPackageJSON 读取 npm 包中的 package/package.json
+func readPackageJSON(tarball string) (map[string]interface{}, error) {
+	var pkg map[string]interface{}
+	err := walkTarGz(tarball, func(header *tar.Header, r io.Reader) error {
+		if header.Name != "package/package.json" || pkg != nil {
+			return nil
+		}
+		return json.NewDecoder(r).Decode(&pkg)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取 npm 包失败: %w", err)
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("npm 包中缺少 package/package.json")
+	}
+	return pkg, nil
+}
+
+// Publish 发布 npm 包，返回包名和版本号
+func (p *NPMPublisher) Publish(tarball string) (string, string, error) {
+	pkg, err := readPackageJSON(tarball)
+	if err != nil {
+		return "", "", err
+	}
+
+	name, _ := pkg["name"].(string)
+	version, _ := pkg["version"].(string)
+	if name == "" || version == "" {
+		return "", "", fmt.Errorf("package.json 缺少 name 或 version")
+	}
+
+	data, err := os.ReadFile(tarball)
+	if err != nil {
+		return "", "", fmt.Errorf("读取 npm 包失败: %w", err)
+	}
+
+	sha1Sum := sha1.Sum(data)
+	sha512Sum := sha512.Sum512(data)
+	registry := strings.TrimSuffix(p.config.Registry, "/")
+	unscoped := name[strings.LastIndex(name, "/")+1:]
+	attachment := fmt.Sprintf("%s-%s.tgz", unscoped, version)
+
+	pkg["_id"] = name + "@" + version
+	pkg["dist"] = map[string]interface{}{
+		"shasum":    hex.EncodeToString(sha1Sum[:]),
+		"integrity": "sha512-" + base64.StdEncoding.EncodeToString(sha512Sum[:]),
+		"tarball":   fmt.Sprintf("%s/%s/-/%s", registry, name, attachment),
+	}
+
+	document := map[string]interface{}{
+		"_id":       name,
+		"name":      name,
+		"dist-tags": map[string]string{p.config.Tag: version},
+		"versions":  map[string]interface{}{version: pkg},
+		"_attachments": map[string]interface{}{
+			attachment: map[string]interface{}{
+				"content_type": "application/octet-stream",
+				"data":         base64.StdEncoding.EncodeToString(data),
+				"length":       len(data),
+			},
+		},
+	}
+	if p.config.Access != "" {
+		document["access"] = p.config.Access
+	}
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		return "", "", fmt.Errorf("生成发布请求失败: %w", err)
+	}
+
+	// 作用域包名中的 / 需要编码，例如 @scope%2fname
+	req, err := http.NewRequest(http.MethodPut, registry+"/"+strings.Replace(url.PathEscape(name), "%40", "@", 1), bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	req.Header.Set("npm-command", "publish")
+
+	if _, err := request(req); err != nil {
+		return "", "", err
+	}
+
+	return name, version, nil
+}
@@ -0,0 +1,149 @@
+package publish
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"deploy/internal/config"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePackage 创建包含 package/package.json 的 npm 包
+func writePackage(t *testing.T, name, version string) (string, []byte) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	files := map[string]string{
+		"package/package.json": `{"name":"` + name + `","version":"` + version + `"}`,
+		"package/index.js":     "module.exports = 1",
+	}
+	for _, fileName := range []string{"package/package.json", "package/index.js"} {
+		content := files[fileName]
+		if err := tw.WriteHeader(&tar.Header{Name: fileName, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+
+	path := filepath.Join(t.TempDir(), "package.tgz")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, buf.Bytes()
+}
+
+// npmPublishRequest 模拟仓库收到的发布请求
+type npmPublishRequest struct {
+	Method  string
+	Path    string
+	Header  http.Header
+	Payload struct {
+		Name        string                     `json:"name"`
+		DistTags    map[string]string          `json:"dist-tags"`
+		Access      string                     `json:"access"`
+		Versions    map[string]json.RawMessage `json:"versions"`
+		Attachments map[string]struct {
+			Data   string `json:"data"`
+			Length int    `json:"length"`
+		} `json:"_attachments"`
+	}
+}
+
+func newFakeNPMRegistry(t *testing.T, status int) (*npmPublishRequest, *httptest.Server) {
+	received := &npmPublishRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received.Method = req.Method
+		received.Path = req.URL.EscapedPath()
+		received.Header = req.Header.Clone()
+		body, _ := io.ReadAll(req.Body)
+		if err := json.Unmarshal(body, &received.Payload); err != nil {
+			t.Errorf("发布请求不是有效的 JSON: %v", err)
+		}
+		w.WriteHeader(status)
+		if status >= 300 {
+			w.Write([]byte(`{"error":"forbidden"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return received, server
+}
+
+func TestNPMPublish(t *testing.T) {
+	received, server := newFakeNPMRegistry(t, http.StatusOK)
+	publisher, err := NewNPMPublisher(config.NPMPublishConfig{Registry: server.URL + "/", Token: "npm_token", Tag: "next", Access: "public"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tarball, data := writePackage(t, "@scope/web", "2.0.0")
+	name, version, err := publisher.Publish(tarball)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "@scope/web" || version != "2.0.0" {
+		t.Errorf("返回 %s@%s", name, version)
+	}
+
+	if received.Method != http.MethodPut || received.Path != "/@scope%2Fweb" {
+		t.Errorf("请求为 %s %s，应为 PUT /@scope%%2Fweb", received.Method, received.Path)
+	}
+	if got := received.Header.Get("Authorization"); got != "Bearer npm_token" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := received.Header.Get("npm-command"); got != "publish" {
+		t.Errorf("npm-command = %q", got)
+	}
+
+	payload := received.Payload
+	if payload.Name != "@scope/web" || payload.DistTags["next"] != "2.0.0" || payload.Access != "public" {
+		t.Errorf("发布内容不正确: name=%s dist-tags=%v access=%s", payload.Name, payload.DistTags, payload.Access)
+	}
+	if _, ok := payload.Versions["2.0.0"]; !ok {
+		t.Error("versions 中缺少 2.0.0")
+	}
+	attachment, ok := payload.Attachments["web-2.0.0.tgz"]
+	if !ok {
+		t.Fatalf("_attachments 中缺少 web-2.0.0.tgz: %v", payload.Attachments)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(attachment.Data)
+	if err != nil || !bytes.Equal(decoded, data) || attachment.Length != len(data) {
+		t.Error("上传的 npm 包内容与原始文件不一致")
+	}
+}
+
+func TestNPMPublishStatusError(t *testing.T) {
+	_, server := newFakeNPMRegistry(t, http.StatusForbidden)
+	publisher, err := NewNPMPublisher(config.NPMPublishConfig{Registry: server.URL, Token: "npm_token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tarball, _ := writePackage(t, "web", "1.0.0")
+	_, _, err = publisher.Publish(tarball)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("应返回 403 StatusError，实际为 %v", err)
+	}
+	if statusErr.Body != `{"error":"forbidden"}` {
+		t.Errorf("错误中应包含响应内容: %v", err)
+	}
+}
+
+func TestNewNPMPublisherRequiresToken(t *testing.T) {
+	if _, err := NewNPMPublisher(config.NPMPublishConfig{}); err == nil {
+		t.Fatal("未配置 token 时应返回错误")
+	}
+}
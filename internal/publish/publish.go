@@ -0,0 +1,56 @@
+package publish
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout HTTP 请求超时时间
+const defaultTimeout = 5 * time.Minute
+
+// httpClient 发布使用的 HTTP 客户端
+var httpClient = &http.Client{Timeout: defaultTimeout}
+
+// request 发送 HTTP 请求，非 2xx 响应返回错误
+func request(req *http.Request) ([]byte, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s 失败: %w", req.Method, req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return body, &StatusError{
+			Method:     req.Method,
+			URL:        req.URL.Redacted(),
+			StatusCode: resp.StatusCode,
+			Body:       string(bytes.TrimSpace(body)),
+		}
+	}
+
+	return body, nil
+}
+
+// StatusError HTTP 状态码错误
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+// Error 实现 error 接口
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s %s 返回 %d", e.Method, e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s 返回 %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
@@ -0,0 +1,451 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultRegion 默认区域，MinIO 等兼容服务通常使用该值
+	defaultRegion = "us-east-1"
+	// defaultPartSize 默认分片大小
+	defaultPartSize = 16 * 1024 * 1024
+	// minPartSize S3 允许的最小分片大小（最后一个分片除外）
+	minPartSize = 5 * 1024 * 1024
+	// emptyPayloadHash 空请求体的 SHA-256
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// Options S3 客户端配置
+type Options struct {
+	Endpoint         string // 例如 https://minio.example.com，为空时使用 AWS S3
+	Region           string
+	Bucket           string
+	AccessKey        string
+	SecretKey        string
+	VirtualHostStyle bool  // 使用 bucket.endpoint 形式的地址，默认使用路径形式 endpoint/bucket
+	PartSize         int64 // 分片上传的分片大小，超过该大小的文件使用分片上传
+}
+
+// Client S3 兼容对象存储客户端，使用 AWS Signature Version 4 签名
+type Client struct {
+	options Options
+	http    *http.Client
+	now     func() time.Time
+}
+
+// NewClient 创建客户端
+func NewClient(options Options) (*Client, error) {
+	if options.Bucket == "" {
+		return nil, fmt.Errorf("未配置 bucket")
+	}
+	if options.AccessKey == "" || options.SecretKey == "" {
+		return nil, fmt.Errorf("未配置 access_key/secret_key")
+	}
+	if options.Region == "" {
+		options.Region = defaultRegion
+	}
+	if options.Endpoint == "" {
+		options.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", options.Region)
+	}
+	if options.PartSize == 0 {
+		options.PartSize = defaultPartSize
+	}
+	if options.PartSize < minPartSize {
+		return nil, fmt.Errorf("分片大小不能小于 5MB")
+	}
+
+	return &Client{
+		options: options,
+		http:    &http.Client{Timeout: 30 * time.Minute},
+		now:     time.Now,
+	}, nil
+}
+
+// objectURL 获取对象地址
+func (c *Client) objectURL(key string, query url.Values) (*url.URL, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(c.options.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("endpoint 无效: %w", err)
+	}
+
+	u := *endpoint
+	if c.options.VirtualHostStyle {
+		u.Host = c.options.Bucket + "." + endpoint.Host
+		u.Path = endpoint.Path + "/" + key
+	} else {
+		u.Path = endpoint.Path + "/" + c.options.Bucket + "/" + key
+	}
+	u.RawPath = uriEncodePath(u.Path)
+	u.RawQuery = canonicalQuery(query)
+	return &u, nil
+}
+
+// PutFile 上传文件，大文件自动使用分片上传
+func (c *Client) PutFile(key, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() > c.options.PartSize {
+		return c.multipartUpload(key, file, info.Size())
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	return c.PutObject(key, data)
+}
+
+// PutObject 上传对象
+func (c *Client) PutObject(key string, data []byte) error {
+	_, _, err := c.do(http.MethodPut, key, nil, data)
+	return err
+}
+
+// GetObject 下载对象到 w
+func (c *Client) GetObject(key string, w io.Writer) error {
+	u, err := c.objectURL(key, nil)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	c.sign(req, emptyPayloadHash)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("下载 %s 失败: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return responseError(http.MethodGet, key, resp.StatusCode, body)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// ListKeys 列出指定前缀下的对象
+func (c *Client) ListKeys(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		body, _, err := c.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("解析对象列表失败: %w", err)
+		}
+
+		for _, content := range result.Contents {
+			keys = append(keys, content.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// completedPart 已上传的分片
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// multipartUpload 分片上传，失败时中止上传以释放已上传的分片
+func (c *Client) multipartUpload(key string, r io.Reader, size int64) error {
+	body, _, err := c.do(http.MethodPost, key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+
+	var initiate struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(body, &initiate); err != nil || initiate.UploadID == "" {
+		return fmt.Errorf("创建分片上传失败: %s", string(body))
+	}
+
+	parts, err := c.uploadParts(key, initiate.UploadID, r, size)
+	if err != nil {
+		c.do(http.MethodDelete, key, url.Values{"uploadId": {initiate.UploadID}}, nil)
+		return err
+	}
+
+	complete := struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts}
+	data, err := xml.Marshal(complete)
+	if err != nil {
+		return err
+	}
+
+	body, _, err = c.do(http.MethodPost, key, url.Values{"uploadId": {initiate.UploadID}}, data)
+	if err != nil {
+		c.do(http.MethodDelete, key, url.Values{"uploadId": {initiate.UploadID}}, nil)
+		return err
+	}
+
+	// CompleteMultipartUpload 出错时也可能返回 200，需要检查响应内容
+	if bytes.Contains(body, []byte("<Error>")) {
+		return fmt.Errorf("完成分片上传失败: %s", string(body))
+	}
+	return nil
+}
+
+// uploadParts 依次上传所有分片
+func (c *Client) uploadParts(key, uploadID string, r io.Reader, size int64) ([]completedPart, error) {
+	var parts []completedPart
+	buf := make([]byte, c.options.PartSize)
+
+	for partNumber := 1; int64(partNumber-1)*c.options.PartSize < size; partNumber++ {
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("读取分片失败: %w", err)
+		}
+
+		query := url.Values{
+			"partNumber": {strconv.Itoa(partNumber)},
+			"uploadId":   {uploadID},
+		}
+		_, header, err := c.do(http.MethodPut, key, query, buf[:n])
+		if err != nil {
+			return nil, fmt.Errorf("上传第 %d 个分片失败: %w", partNumber, err)
+		}
+
+		parts = append(parts, completedPart{PartNumber: partNumber, ETag: header.Get("ETag")})
+	}
+
+	return parts, nil
+}
+
+// do 发送签名请求，非 2xx 响应返回错误
+func (c *Client) do(method, key string, query url.Values, body []byte) ([]byte, http.Header, error) {
+	u, err := c.objectURL(key, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.ContentLength = int64(len(body))
+
+	payloadHash := sha256.Sum256(body)
+	c.sign(req, hex.EncodeToString(payloadHash[:]))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s 失败: %w", method, key, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, responseError(method, key, resp.StatusCode, respBody)
+	}
+
+	return respBody, resp.Header, nil
+}
+
+// Error S3 错误响应
+type Error struct {
+	Method     string
+	Key        string
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s 返回 %d %s: %s", e.Method, e.Key, e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound 是否为对象不存在错误
+func IsNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// responseError 解析错误响应
+func responseError(method, key string, statusCode int, body []byte) error {
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	xml.Unmarshal(body, &s3Err)
+
+	return &Error{
+		Method:     method,
+		Key:        key,
+		StatusCode: statusCode,
+		Code:       s3Err.Code,
+		Message:    s3Err.Message,
+	}
+}
+
+// sign 使用 AWS Signature Version 4 为请求签名
+func (c *Client) sign(req *http.Request, payloadHash string) {
+	now := c.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// 参与签名的请求头
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "range" || lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, c.options.Region, "s3", "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.options.SecretKey), date)
+	key = hmacSHA256(key, c.options.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.options.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// uriEncode 按 AWS 规则编码，只保留 A-Z a-z 0-9 - _ . ~
+func uriEncode(s string, encodeSlash bool) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch >= 'A' && ch <= 'Z', ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~':
+			buf.WriteByte(ch)
+		case ch == '/' && !encodeSlash:
+			buf.WriteByte(ch)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", ch)
+		}
+	}
+	return buf.String()
+}
+
+// uriEncodePath 编码对象路径
+func uriEncodePath(path string) string {
+	return uriEncode(path, false)
+}
+
+// canonicalQuery 生成按键排序的查询字符串
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
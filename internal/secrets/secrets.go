@@ -0,0 +1,302 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// 加密值的格式为 ENC[<算法>,<base64 密文>]
+const (
+	AlgorithmAES = "aes256gcm" // 对称密钥文件 (AES-256-GCM)
+	AlgorithmAge = "age"       // age 公钥加密
+
+	prefix = "ENC["
+	suffix = "]"
+)
+
+// keySize 对称密钥长度 (AES-256)
+const keySize = 32
+
+// Options 密钥配置
+type Options struct {
+	KeyFile       string   // 对称密钥文件，为空时使用 DEPLOY_SECRET_KEY 或 ~/.deploy/secret.key
+	AgeRecipients []string // age 公钥，配置后加密使用 age
+	AgeIdentity   string   // age 私钥文件，为空时使用 DEPLOY_AGE_IDENTITY 或 ~/.deploy/age.key
+}
+
+// Keyring 加密和解密配置值，密钥在首次使用时加载
+type Keyring struct {
+	options    Options
+	key        []byte
+	identities []age.Identity
+}
+
+// New 创建 Keyring
+func New(options Options) *Keyring {
+	return &Keyring{options: options}
+}
+
+// IsEncrypted 检查值是否为加密值
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix) && strings.HasSuffix(value, suffix) && strings.Contains(value, ",")
+}
+
+// Encrypt 加密值，配置了 age 公钥时使用 age，否则使用对称密钥文件
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if len(k.options.AgeRecipients) > 0 {
+		return k.encryptAge(plaintext)
+	}
+	return k.encryptAES(plaintext)
+}
+
+// Decrypt 解密 ENC[...] 格式的值
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", fmt.Errorf("不是加密值，应为 ENC[<算法>,<密文>] 格式")
+	}
+
+	algorithm, encoded, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(value, prefix), suffix), ",")
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("加密值格式错误: %w", err)
+	}
+
+	switch algorithm {
+	case AlgorithmAES:
+		return k.decryptAES(data)
+	case AlgorithmAge:
+		return k.decryptAge(data)
+	default:
+		return "", fmt.Errorf("不支持的加密算法: %s (可选 %s, %s)", algorithm, AlgorithmAES, AlgorithmAge)
+	}
+}
+
+// encryptAES 使用对称密钥加密，密文为随机 nonce 加 AES-GCM 输出
+func (k *Keyring) encryptAES(plaintext string) (string, error) {
+	gcm, err := k.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	data := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return format(AlgorithmAES, data), nil
+}
+
+// decryptAES 使用对称密钥解密
+func (k *Keyring) decryptAES(data []byte) (string, error) {
+	gcm, err := k.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("加密值格式错误: 密文过短")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("解密失败，密钥不匹配或密文已损坏")
+	}
+	return string(plaintext), nil
+}
+
+// cipher 加载对称密钥并创建 AES-GCM
+func (k *Keyring) cipher() (cipher.AEAD, error) {
+	if k.key == nil {
+		key, err := k.loadKey()
+		if err != nil {
+			return nil, err
+		}
+		k.key = key
+	}
+
+	block, err := aes.NewCipher(k.key)
+	if err != nil {
+		return nil, fmt.Errorf("密钥无效: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadKey 读取对称密钥，DEPLOY_SECRET_KEY 环境变量优先
+func (k *Keyring) loadKey() ([]byte, error) {
+	source := "DEPLOY_SECRET_KEY"
+	encoded := os.Getenv("DEPLOY_SECRET_KEY")
+	if encoded == "" {
+		path := k.options.KeyFile
+		if path == "" {
+			path = DefaultKeyFile()
+		}
+		data, err := os.ReadFile(ExpandHome(path))
+		if err != nil {
+			return nil, fmt.Errorf("读取密钥文件失败: %w (可使用 deploy secrets keygen 生成)", err)
+		}
+		source, encoded = path, string(data)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("密钥无效 (%s)，应为 %d 字节的 base64 编码", source, keySize)
+	}
+	return key, nil
+}
+
+// encryptAge 使用 age 公钥加密
+func (k *Keyring) encryptAge(plaintext string) (string, error) {
+	recipients, err := age.ParseRecipients(strings.NewReader(strings.Join(k.options.AgeRecipients, "\n")))
+	if err != nil {
+		return "", fmt.Errorf("解析 age 公钥失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writer, err := age.Encrypt(&buf, recipients...)
+	if err != nil {
+		return "", fmt.Errorf("age 加密失败: %w", err)
+	}
+	if _, err := io.WriteString(writer, plaintext); err != nil {
+		return "", fmt.Errorf("age 加密失败: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("age 加密失败: %w", err)
+	}
+	return format(AlgorithmAge, buf.Bytes()), nil
+}
+
+// decryptAge 使用 age 私钥解密
+func (k *Keyring) decryptAge(data []byte) (string, error) {
+	if k.identities == nil {
+		identities, err := k.loadIdentities()
+		if err != nil {
+			return "", err
+		}
+		k.identities = identities
+	}
+
+	reader, err := age.Decrypt(bytes.NewReader(data), k.identities...)
+	if err != nil {
+		return "", fmt.Errorf("age 解密失败: %w", err)
+	}
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("age 解密失败: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// loadIdentities 读取 age 私钥文件，DEPLOY_AGE_IDENTITY 环境变量优先
+func (k *Keyring) loadIdentities() ([]age.Identity, error) {
+	path := os.Getenv("DEPLOY_AGE_IDENTITY")
+	if path == "" {
+		path = k.options.AgeIdentity
+	}
+	if path == "" {
+		path = DefaultIdentityFile()
+	}
+
+	file, err := os.Open(ExpandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("读取 age 私钥文件失败: %w", err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("解析 age 私钥文件失败: %w", err)
+	}
+	return identities, nil
+}
+
+// GenerateKey 生成对称密钥文件，文件已存在时返回错误
+func GenerateKey(path string) error {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("生成密钥失败: %w", err)
+	}
+	return writeKeyFile(path, base64.StdEncoding.EncodeToString(key)+"\n")
+}
+
+// GenerateIdentity 生成 age 私钥文件，返回对应的公钥
+func GenerateIdentity(path string) (string, error) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return "", fmt.Errorf("生成 age 私钥失败: %w", err)
+	}
+
+	recipient := identity.Recipient().String()
+	content := fmt.Sprintf("# public key: %s\n%s\n", recipient, identity.String())
+	if err := writeKeyFile(path, content); err != nil {
+		return "", err
+	}
+	return recipient, nil
+}
+
+// writeKeyFile 写入只有当前用户可读的密钥文件
+func writeKeyFile(path, content string) error {
+	path = ExpandHome(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("创建密钥目录失败: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return fmt.Errorf("密钥文件已存在: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("创建密钥文件失败: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("写入密钥文件失败: %w", err)
+	}
+	return nil
+}
+
+// DefaultKeyFile 默认的对称密钥文件，可通过 DEPLOY_HOME 环境变量修改目录
+func DefaultKeyFile() string {
+	return filepath.Join(deployHome(), "secret.key")
+}
+
+// DefaultIdentityFile 默认的 age 私钥文件
+func DefaultIdentityFile() string {
+	return filepath.Join(deployHome(), "age.key")
+}
+
+// deployHome 获取 deploy 目录，默认为 ~/.deploy
+func deployHome() string {
+	if home := os.Getenv("DEPLOY_HOME"); home != "" {
+		return home
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".deploy")
+	}
+	return ".deploy"
+}
+
+// ExpandHome 展开路径开头的 ~/
+func ExpandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// format 生成 ENC[<算法>,<base64 密文>]
+func format(algorithm string, data []byte) string {
+	return prefix + algorithm + "," + base64.StdEncoding.EncodeToString(data) + suffix
+}
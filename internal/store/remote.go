@@ -0,0 +1,170 @@
+package store
+
+import (
+	"bytes"
+	"deploy/internal/manifest"
+	"deploy/internal/s3"
+	"deploy/internal/utils"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Remote 远程构建产物仓库，使用 S3 兼容对象存储
+//
+// 对象结构与本地仓库一致：
+//
+//	<prefix>/<project>/<version>/
+//	  record.json
+//	  <artifact>
+//	  <artifact>.manifest.json
+//	  <artifact>.sha256
+type Remote struct {
+	client *s3.Client
+	prefix string
+}
+
+// NewRemote 创建远程仓库
+func NewRemote(client *s3.Client, prefix string) *Remote {
+	return &Remote{client: client, prefix: strings.Trim(prefix, "/")}
+}
+
+// versionKey 获取版本目录的对象前缀
+func (r *Remote) versionKey(project, version string) string {
+	return path.Join(r.prefix, utils.SanitizeFileName(project), utils.SanitizeFileName(version))
+}
+
+// Push 上传本地仓库中的版本，record.json 最后上传，保证远程版本完整可用
+func (r *Remote) Push(local *Store, record *Record) error {
+	artifactPath := local.ArtifactPath(record)
+	info, err := os.Stat(artifactPath)
+	if err != nil {
+		return fmt.Errorf("构建产物不存在: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("远程仓库不支持 dir 格式的构建产物")
+	}
+
+	prefix := r.versionKey(record.Project, record.Version)
+	for _, file := range []string{artifactPath, manifest.ManifestPath(artifactPath), manifest.ChecksumPath(artifactPath)} {
+		if !utils.FileExists(file) {
+			continue
+		}
+		if err := r.client.PutFile(path.Join(prefix, filepath.Base(file)), file); err != nil {
+			return fmt.Errorf("上传 %s 失败: %w", filepath.Base(file), err)
+		}
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化记录失败: %w", err)
+	}
+	if err := r.client.PutObject(path.Join(prefix, recordFile), append(data, '\n')); err != nil {
+		return fmt.Errorf("上传记录失败: %w", err)
+	}
+
+	return nil
+}
+
+// Get 获取远程版本的记录，version 为 latest 时返回最新版本
+func (r *Remote) Get(project, version string) (*Record, error) {
+	if version == "latest" {
+		return r.latest(project)
+	}
+
+	var buf bytes.Buffer
+	err := r.client.GetObject(path.Join(r.versionKey(project, version), recordFile), &buf)
+	if s3.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: 远程仓库中没有 %s@%s", ErrNotFound, project, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取远程记录失败: %w", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		return nil, fmt.Errorf("解析远程记录失败: %w", err)
+	}
+	return &record, nil
+}
+
+// latest 获取远程仓库中最新的版本
+func (r *Remote) latest(project string) (*Record, error) {
+	keys, err := r.client.ListKeys(path.Join(r.prefix, utils.SanitizeFileName(project)) + "/")
+	if err != nil {
+		return nil, fmt.Errorf("列出远程版本失败: %w", err)
+	}
+
+	var latest *Record
+	for _, key := range keys {
+		if path.Base(key) != recordFile {
+			continue
+		}
+		record, err := r.Get(project, path.Base(path.Dir(key)))
+		if err != nil {
+			continue
+		}
+		if latest == nil || record.CreatedAt.After(latest.CreatedAt) {
+			latest = record
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("%w: 远程仓库中 %s 没有任何版本", ErrNotFound, project)
+	}
+	return latest, nil
+}
+
+// Pull 下载远程版本到本地仓库，校验和不匹配时不会写入本地仓库
+func (r *Remote) Pull(local *Store, project, version string) (*Record, error) {
+	record, err := r.Get(project, version)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir, err := os.MkdirTemp("", "deploy-pull-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	prefix := r.versionKey(record.Project, record.Version)
+	artifactPath := filepath.Join(tempDir, record.Artifact)
+	for _, file := range []string{artifactPath, manifest.ManifestPath(artifactPath), manifest.ChecksumPath(artifactPath)} {
+		err := r.download(path.Join(prefix, filepath.Base(file)), file)
+		if err != nil && !(s3.IsNotFound(err) && file != artifactPath) {
+			return nil, fmt.Errorf("下载 %s 失败: %w", filepath.Base(file), err)
+		}
+	}
+
+	actual, _, err := manifest.FileSHA256(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("计算校验和失败: %w", err)
+	}
+	if record.Checksum != "" && actual != record.Checksum {
+		return nil, fmt.Errorf("校验和不匹配: 期望 %s，实际 %s", record.Checksum, actual)
+	}
+
+	if err := local.Save(record, artifactPath); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// download 下载对象到本地文件
+func (r *Remote) download(key, dst string) error {
+	file, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if err := r.client.GetObject(key, file); err != nil {
+		file.Close()
+		os.Remove(dst)
+		return err
+	}
+	return file.Close()
+}
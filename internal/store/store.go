@@ -0,0 +1,356 @@
+package store
+
+import (
+	"deploy/internal/manifest"
+	"deploy/internal/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// recordFile 每个版本目录中的记录文件
+const recordFile = "record.json"
+
+// ErrNotFound 构建产物不存在
+var ErrNotFound = errors.New("构建产物不存在")
+
+// Record 构建产物记录
+type Record struct {
+	Project   string    `json:"project"`
+	Version   string    `json:"version"`
+	Artifact  string    `json:"artifact"` // 版本目录中的产物文件名
+	Builder   string    `json:"builder"`
+	Checksum  string    `json:"checksum"`
+	Size      int64     `json:"size"`
+	BuildTime string    `json:"build_time"`
+	Files     []string  `json:"files,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	CacheKey  string    `json:"cache_key,omitempty"` // 构建缓存键，见 builder 包
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store 本地构建产物仓库
+//
+// 目录结构：
+//
+//	<root>/<project>/<version>/
+//	  record.json
+//	  <artifact>
+//	  <artifact>.manifest.json
+//	  <artifact>.sha256
+type Store struct {
+	root string
+}
+
+// DefaultRoot 获取默认仓库目录，可通过 DEPLOY_HOME 环境变量修改
+func DefaultRoot() string {
+	if home := os.Getenv("DEPLOY_HOME"); home != "" {
+		return filepath.Join(home, "artifacts")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".deploy", "artifacts")
+	}
+	return filepath.Join(".deploy", "artifacts")
+}
+
+// New 创建仓库，root 为空时使用默认目录
+func New(root string) *Store {
+	if root == "" {
+		root = DefaultRoot()
+	}
+	return &Store{root: root}
+}
+
+// Root 获取仓库根目录
+func (s *Store) Root() string {
+	return s.root
+}
+
+// versionDir 获取版本目录
+func (s *Store) versionDir(project, version string) string {
+	return filepath.Join(s.root, utils.SanitizeFileName(project), utils.SanitizeFileName(version))
+}
+
+// Save 将构建产物及其清单复制到仓库并写入记录
+func (s *Store) Save(record *Record, artifactPath string) error {
+	dir := s.versionDir(record.Project, record.Version)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("清理版本目录失败: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建版本目录失败: %w", err)
+	}
+
+	record.Artifact = filepath.Base(artifactPath)
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	if err := copyPath(artifactPath, filepath.Join(dir, record.Artifact)); err != nil {
+		return fmt.Errorf("复制构建产物失败: %w", err)
+	}
+
+	// 清单和校验文件是可选的
+	for _, sidecar := range []string{manifest.ManifestPath(artifactPath), manifest.ChecksumPath(artifactPath)} {
+		if !utils.FileExists(sidecar) {
+			continue
+		}
+		if err := utils.CopyFile(sidecar, filepath.Join(dir, filepath.Base(sidecar))); err != nil {
+			return fmt.Errorf("复制 %s 失败: %w", filepath.Base(sidecar), err)
+		}
+	}
+
+	return s.writeRecord(record)
+}
+
+// writeRecord 写入记录文件
+func (s *Store) writeRecord(record *Record) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化记录失败: %w", err)
+	}
+
+	path := filepath.Join(s.versionDir(record.Project, record.Version), recordFile)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("写入记录失败: %w", err)
+	}
+	return nil
+}
+
+// Get 获取指定版本的记录，version 为 latest 时返回最新版本
+func (s *Store) Get(project, version string) (*Record, error) {
+	if version == "latest" {
+		records, err := s.List(project)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("%w: %s 没有任何版本", ErrNotFound, project)
+		}
+		return records[0], nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.versionDir(project, version), recordFile))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s@%s", ErrNotFound, project, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取记录失败: %w", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("解析记录失败: %w", err)
+	}
+	return &record, nil
+}
+
+// ArtifactPath 获取记录对应的构建产物路径
+func (s *Store) ArtifactPath(record *Record) string {
+	return filepath.Join(s.versionDir(record.Project, record.Version), record.Artifact)
+}
+
+// FindByCacheKey 查找缓存键相同的最新版本
+func (s *Store) FindByCacheKey(project, cacheKey string) (*Record, error) {
+	records, err := s.List(project)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if record.CacheKey == cacheKey {
+			return record, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s 没有缓存键为 %s 的版本", ErrNotFound, project, cacheKey)
+}
+
+// Export 将版本的构建产物及其清单复制到指定目录，返回复制后的构建产物路径
+func (s *Store) Export(record *Record, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	artifactPath := s.ArtifactPath(record)
+	target := filepath.Join(dir, record.Artifact)
+	if err := os.RemoveAll(target); err != nil {
+		return "", fmt.Errorf("清理 %s 失败: %w", target, err)
+	}
+	if err := copyPath(artifactPath, target); err != nil {
+		return "", fmt.Errorf("复制构建产物失败: %w", err)
+	}
+
+	for _, sidecar := range []string{manifest.ManifestPath(artifactPath), manifest.ChecksumPath(artifactPath)} {
+		if !utils.FileExists(sidecar) {
+			continue
+		}
+		if err := utils.CopyFile(sidecar, filepath.Join(dir, filepath.Base(sidecar))); err != nil {
+			return "", fmt.Errorf("复制 %s 失败: %w", filepath.Base(sidecar), err)
+		}
+	}
+
+	return target, nil
+}
+
+// List 列出项目的所有版本，按创建时间从新到旧排序
+func (s *Store) List(project string) ([]*Record, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, utils.SanitizeFileName(project)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取仓库目录失败: %w", err)
+	}
+
+	var records []*Record
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		record, err := s.Get(project, entry.Name())
+		if err != nil {
+			// 忽略不完整的版本目录
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+// Projects 列出仓库中的所有项目
+func (s *Store) Projects() ([]string, error) {
+	entries, err := os.ReadDir(s.root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取仓库目录失败: %w", err)
+	}
+
+	var projects []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			projects = append(projects, entry.Name())
+		}
+	}
+	return projects, nil
+}
+
+// Tag 为版本添加标签，带标签的版本在清理时可以保留
+func (s *Store) Tag(project, version, tag string) error {
+	record, err := s.Get(project, version)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range record.Tags {
+		if t == tag {
+			return nil
+		}
+	}
+	record.Tags = append(record.Tags, tag)
+	return s.writeRecord(record)
+}
+
+// Untag 移除版本的标签
+func (s *Store) Untag(project, version, tag string) error {
+	record, err := s.Get(project, version)
+	if err != nil {
+		return err
+	}
+
+	var tags []string
+	for _, t := range record.Tags {
+		if t != tag {
+			tags = append(tags, t)
+		}
+	}
+	record.Tags = tags
+	return s.writeRecord(record)
+}
+
+// Remove 删除指定版本
+func (s *Store) Remove(project, version string) error {
+	if _, err := s.Get(project, version); err != nil {
+		return err
+	}
+	return os.RemoveAll(s.versionDir(project, version))
+}
+
+// PruneOptions 清理规则
+type PruneOptions struct {
+	KeepLast   int  // 保留最新的 N 个版本
+	KeepTagged bool // 保留带标签的版本
+	DryRun     bool // 只返回将被删除的版本，不实际删除
+}
+
+// Prune 按保留规则清理旧版本，返回被删除的记录
+func (s *Store) Prune(project string, opts PruneOptions) ([]*Record, error) {
+	records, err := s.List(project)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []*Record
+	for i, record := range records {
+		if i < opts.KeepLast {
+			continue
+		}
+		if opts.KeepTagged && len(record.Tags) > 0 {
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := os.RemoveAll(s.versionDir(project, record.Version)); err != nil {
+				return removed, fmt.Errorf("删除 %s 失败: %w", record.Version, err)
+			}
+		}
+		removed = append(removed, record)
+	}
+
+	return removed, nil
+}
+
+// copyPath 复制文件或目录
+func copyPath(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return utils.CopyFile(src, dst)
+	}
+
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, 0755)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return utils.CopyFile(path, target)
+		}
+	})
+}
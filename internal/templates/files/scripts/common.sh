@@ -0,0 +1,34 @@
+#!/usr/bin/env bash
+# common.sh 部署脚本的通用函数，由 java-deploy.sh 和 npm-deploy.sh 引用
+#
+# 脚本位于项目或版本目录的 scripts/ 中，APP_HOME 为其上级目录。
+
+set -euo pipefail
+
+APP_HOME="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+
+# log 输出带时间的日志
+log() {
+  echo "[$(date '+%Y-%m-%d %H:%M:%S')] $*"
+}
+
+# die 输出错误并退出
+die() {
+  log "错误: $*" >&2
+  exit 1
+}
+
+# require_command 检查命令是否存在
+require_command() {
+  command -v "$1" >/dev/null 2>&1 || die "未找到命令: $1"
+}
+
+# require_file 检查文件是否存在
+require_file() {
+  [ -f "$1" ] || die "文件不存在: $1"
+}
+
+# require_env 检查环境变量是否已设置，可在 deploy.yaml 的 scripts.variables 中设置
+require_env() {
+  [ -n "${!1:-}" ] || die "未设置环境变量 $1，请在 deploy.yaml 的 scripts.variables 中设置"
+}
@@ -0,0 +1,59 @@
+#!/usr/bin/env bash
+# java-deploy.sh 在前台运行 Java 服务，用作 systemd 的 exec_start (工作目录为 <deploy_path>/current)
+#
+# 用法:
+#   java-deploy.sh jar      运行版本目录中的 <APP_NAME>.jar
+#   java-deploy.sh tomcat   将版本目录中的 <APP_NAME>.war 部署为 Tomcat 的 ROOT 应用并运行 Tomcat
+#
+# 环境变量 (在 deploy.yaml 的 scripts.variables 中设置):
+#   APP_NAME        应用名称，与 project.name 相同
+#   JAVA_OPTS       JVM 参数 (jar)
+#   APP_OPTS        应用参数 (jar)
+#   CATALINA_HOME   Tomcat 安装目录 (tomcat)，默认为 /opt/tomcat
+#   CATALINA_BASE   Tomcat 实例目录 (tomcat)，默认为 <deploy_path>/tomcat
+#   CATALINA_OPTS   Tomcat 的 JVM 参数 (tomcat)
+
+source "$(dirname "${BASH_SOURCE[0]}")/common.sh"
+
+run_jar() {
+  require_env APP_NAME
+  require_command java
+  local jar="$APP_HOME/$APP_NAME.jar"
+  require_file "$jar"
+
+  log "启动 $jar"
+  # shellcheck disable=SC2086
+  exec java ${JAVA_OPTS:-} -jar "$jar" ${APP_OPTS:-}
+}
+
+run_tomcat() {
+  require_env APP_NAME
+  local war="$APP_HOME/$APP_NAME.war"
+  require_file "$war"
+
+  export CATALINA_HOME="${CATALINA_HOME:-/opt/tomcat}"
+  export CATALINA_BASE="${CATALINA_BASE:-$(dirname "$APP_HOME")/tomcat}"
+  require_file "$CATALINA_HOME/bin/catalina.sh"
+
+  # 首次运行时从 CATALINA_HOME 复制配置，创建独立的实例目录
+  if [ ! -d "$CATALINA_BASE/conf" ]; then
+    log "创建 Tomcat 实例目录 $CATALINA_BASE"
+    mkdir -p "$CATALINA_BASE"
+    cp -r "$CATALINA_HOME/conf" "$CATALINA_BASE/"
+  fi
+  mkdir -p "$CATALINA_BASE"/{conf/Catalina/localhost,logs,temp,webapps,work}
+
+  # 每次启动时指向当前版本的 WAR，不解压到 webapps
+  cat >"$CATALINA_BASE/conf/Catalina/localhost/ROOT.xml" <<XML
+<Context docBase="$war" unpackWAR="false" />
+XML
+
+  log "启动 Tomcat ($CATALINA_BASE)，部署 $war"
+  exec "$CATALINA_HOME/bin/catalina.sh" run
+}
+
+case "${1:-jar}" in
+  jar) run_jar ;;
+  tomcat) run_tomcat ;;
+  *) die "用法: $0 [jar|tomcat]" ;;
+esac
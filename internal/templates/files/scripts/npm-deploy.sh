@@ -0,0 +1,48 @@
+#!/usr/bin/env bash
+# npm-deploy.sh 构建和运行 Node 项目
+#
+# 用法:
+#   npm-deploy.sh build   构建项目，用作 npm.build_command (依赖已由 npm.install_command 安装)，
+#                         Next.js standalone 输出会复制 .next/static 和 public
+#   npm-deploy.sh start   在前台运行服务，用作 PM2 的 script
+#
+# 环境变量 (在 deploy.yaml 的 scripts.variables 中设置):
+#   APP_ENTRY      入口文件 (start)，相对版本目录，默认为 server.js
+#   NODE_OPTIONS   Node 参数
+
+source "$(dirname "${BASH_SOURCE[0]}")/common.sh"
+
+build() {
+  require_command npm
+  cd "$APP_HOME"
+
+  log "构建项目"
+  npm run build --if-present
+
+  if [ -d .next/standalone ]; then
+    log "复制静态文件到 .next/standalone"
+    mkdir -p .next/standalone/.next
+    rm -rf .next/standalone/.next/static
+    cp -r .next/static .next/standalone/.next/static
+    if [ -d public ]; then
+      rm -rf .next/standalone/public
+      cp -r public .next/standalone/public
+    fi
+  fi
+}
+
+start() {
+  require_command node
+  cd "$APP_HOME"
+  local entry="${APP_ENTRY:-server.js}"
+  require_file "$entry"
+
+  log "启动 $entry"
+  exec node "$entry"
+}
+
+case "${1:-}" in
+  build) build ;;
+  start) start ;;
+  *) die "用法: $0 build|start" ;;
+esac
@@ -0,0 +1,253 @@
+package templates
+
+import (
+	"bufio"
+	"bytes"
+	"deploy/internal/config"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConfigFile 模板中的配置文件，使用 text/template 渲染，第一行注释为模板说明
+const ConfigFile = "deploy.yaml"
+
+// ScriptsDir 模板中的示例脚本目录，原样写入项目的 scripts/ 目录
+const ScriptsDir = "scripts"
+
+//go:embed files
+var files embed.FS
+
+// builtinTemplates 内置模板及使用的共享脚本 (files/scripts 中的文件)
+var builtinTemplates = []struct {
+	Name    string
+	Scripts []string
+}{
+	{Name: "spring-boot-jar", Scripts: []string{"common.sh", "java-deploy.sh"}},
+	{Name: "spring-boot-war-tomcat", Scripts: []string{"common.sh", "java-deploy.sh"}},
+	{Name: "react-static-nginx", Scripts: []string{"common.sh", "npm-deploy.sh"}},
+	{Name: "node-express-pm2", Scripts: []string{"common.sh", "npm-deploy.sh"}},
+	{Name: "next-standalone", Scripts: []string{"common.sh", "npm-deploy.sh"}},
+}
+
+// Template 项目模板，包含 deploy.yaml 模板和示例脚本
+//
+// 用户模板目录 (默认为 ~/.config/deploy/templates) 中的每个子目录是一个模板：
+//
+//	<名称>/deploy.yaml    配置文件模板
+//	<名称>/scripts/       示例脚本，可选
+//
+// 与内置模板同名的用户模板会替代内置模板。
+type Template struct {
+	Name        string
+	Description string
+	Dir         string // 用户模板所在目录，内置模板为空
+
+	config  []byte
+	scripts fs.FS    // 示例脚本所在目录
+	names   []string // 示例脚本文件名
+}
+
+// Data 渲染 deploy.yaml 模板的变量
+type Data struct {
+	Name        string // 项目名称
+	DeployPath  string // 部署目录
+	Version     int    // 配置文件版本
+	WithScripts bool   // 是否生成示例脚本，模板据此引用 scripts/ 中的脚本
+}
+
+// Builtin 检查是否为内置模板
+func (t *Template) Builtin() bool {
+	return t.Dir == ""
+}
+
+// Scripts 模板中的示例脚本文件名
+func (t *Template) Scripts() []string {
+	return t.names
+}
+
+// Render 渲染 deploy.yaml
+func (t *Template) Render(data Data) ([]byte, error) {
+	content, err := config.RenderTemplate(t.Name, string(t.config), data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+// WriteScripts 将示例脚本写入 dir，已存在的文件在 force 为 false 时跳过，返回写入和跳过的文件
+func (t *Template) WriteScripts(dir string, force bool) (written, skipped []string, err error) {
+	if len(t.names) == 0 {
+		return nil, nil, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("创建脚本目录失败: %w", err)
+	}
+
+	for _, name := range t.names {
+		target := filepath.Join(dir, name)
+		if _, err := os.Stat(target); err == nil && !force {
+			skipped = append(skipped, target)
+			continue
+		}
+
+		content, err := fs.ReadFile(t.scripts, name)
+		if err != nil {
+			return written, skipped, fmt.Errorf("读取模板脚本 %s 失败: %w", name, err)
+		}
+		if err := os.WriteFile(target, content, 0755); err != nil {
+			return written, skipped, fmt.Errorf("写入脚本失败: %w", err)
+		}
+		written = append(written, target)
+	}
+	return written, skipped, nil
+}
+
+// List 获取所有模板，内置模板在前，用户模板按名称排序
+func List() ([]*Template, error) {
+	user, err := userTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Template
+	for _, builtin := range builtinTemplates {
+		if t, ok := user[builtin.Name]; ok {
+			result = append(result, t)
+			delete(user, builtin.Name)
+			continue
+		}
+		t, err := loadBuiltin(builtin.Name, builtin.Scripts)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	names := make([]string, 0, len(user))
+	for name := range user {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result = append(result, user[name])
+	}
+	return result, nil
+}
+
+// Load 按名称加载模板，也可以指定包含 deploy.yaml 的模板目录
+func Load(name string) (*Template, error) {
+	if strings.ContainsRune(name, filepath.Separator) || strings.Contains(name, "/") {
+		return loadDir(name)
+	}
+
+	all, err := List()
+	if err != nil {
+		return nil, err
+	}
+	available := make([]string, len(all))
+	for i, t := range all {
+		if t.Name == name {
+			return t, nil
+		}
+		available[i] = t.Name
+	}
+	return nil, fmt.Errorf("模板不存在: %s\n可用模板: %s", name, strings.Join(available, "、"))
+}
+
+// loadBuiltin 加载内置模板
+func loadBuiltin(name string, scripts []string) (*Template, error) {
+	content, err := files.ReadFile(path.Join("files", name, ConfigFile))
+	if err != nil {
+		return nil, fmt.Errorf("读取内置模板 %s 失败: %w", name, err)
+	}
+	scriptsFS, err := fs.Sub(files, path.Join("files", ScriptsDir))
+	if err != nil {
+		return nil, fmt.Errorf("读取内置模板 %s 失败: %w", name, err)
+	}
+
+	return &Template{
+		Name:        name,
+		Description: description(content),
+		config:      content,
+		scripts:     scriptsFS,
+		names:       scripts,
+	}, nil
+}
+
+// userTemplates 加载用户模板目录中的模板，目录不存在时返回空
+func userTemplates() (map[string]*Template, error) {
+	result := make(map[string]*Template)
+	dir := config.UserTemplatesDir()
+	if dir == "" {
+		return result, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取模板目录失败: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		templateDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(templateDir, ConfigFile)); err != nil {
+			continue
+		}
+		t, err := loadDir(templateDir)
+		if err != nil {
+			return nil, err
+		}
+		result[t.Name] = t
+	}
+	return result, nil
+}
+
+// loadDir 加载模板目录，模板名称为目录名
+func loadDir(dir string) (*Template, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ConfigFile))
+	if err != nil {
+		return nil, fmt.Errorf("读取模板失败: %w", err)
+	}
+
+	t := &Template{
+		Name:        filepath.Base(filepath.Clean(dir)),
+		Description: description(content),
+		Dir:         dir,
+		config:      content,
+	}
+
+	scriptsDir := filepath.Join(dir, ScriptsDir)
+	entries, err := os.ReadDir(scriptsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取模板脚本目录失败: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			t.names = append(t.names, entry.Name())
+		}
+	}
+	t.scripts = os.DirFS(scriptsDir)
+	return t, nil
+}
+
+// description 获取模板第一行注释作为说明
+func description(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	if scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		}
+	}
+	return ""
+}
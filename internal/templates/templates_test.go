@@ -0,0 +1,42 @@
+package templates
+
+import (
+	"deploy/internal/config"
+	"strings"
+	"testing"
+)
+
+// shellOperators 构建命令按空白分割后直接执行，不经过 shell，这些写法不会生效
+var shellOperators = []string{"&&", "||", "|", ";", "(", ")", ">", "<", "$(", "`"}
+
+func TestBuiltinBuildCommandsRunWithoutShell(t *testing.T) {
+	for _, builtin := range builtinTemplates {
+		tmpl, err := loadBuiltin(builtin.Name, builtin.Scripts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, withScripts := range []bool{false, true} {
+			content, err := tmpl.Render(Data{Name: "app", DeployPath: "/opt/app", Version: config.CurrentVersion, WithScripts: withScripts})
+			if err != nil {
+				t.Fatalf("%s: %v", builtin.Name, err)
+			}
+			cfg, err := config.ParseConfig(content, true)
+			if err != nil {
+				t.Fatalf("%s: %v", builtin.Name, err)
+			}
+
+			commands := map[string]string{
+				"npm.install_command": cfg.NPM.InstallCommand,
+				"npm.build_command":   cfg.NPM.BuildCommand,
+				"java.build_command":  cfg.Java.BuildCommand,
+			}
+			for key, command := range commands {
+				for _, operator := range shellOperators {
+					if strings.Contains(command, operator) {
+						t.Errorf("%s (WithScripts=%v): %s 中包含 %q，不经过 shell 执行: %s", builtin.Name, withScripts, key, operator, command)
+					}
+				}
+			}
+		}
+	}
+}
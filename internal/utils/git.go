@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitInfo Git 仓库信息
+type GitInfo struct {
+	Commit string `json:"commit"`
+	Branch string `json:"branch"`
+	Dirty  bool   `json:"dirty"`
+}
+
+// GetGitInfo 获取项目目录的 Git 信息
+func GetGitInfo(dir string) (*GitInfo, error) {
+	commit, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("不是 Git 仓库或没有提交记录: %w", err)
+	}
+
+	branch, err := runGit(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		branch = ""
+	}
+
+	status, err := runGit(dir, "status", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("获取 Git 状态失败: %w", err)
+	}
+
+	return &GitInfo{
+		Commit: commit,
+		Branch: branch,
+		Dirty:  status != "",
+	}, nil
+}
+
+// GetGitCommitTime 获取最近一次提交的时间
+func GetGitCommitTime(dir string) (time.Time, error) {
+	output, err := runGit(dir, "log", "-1", "--format=%ct")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("获取 Git 提交时间失败: %w", err)
+	}
+
+	seconds, err := strconv.ParseInt(output, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("解析 Git 提交时间失败: %w", err)
+	}
+
+	return time.Unix(seconds, 0), nil
+}
+
+// runGit 在指定目录执行 git 命令并返回输出
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GitListFiles 列出 Git 跟踪的文件和未被忽略的新文件，路径相对于 dir
+func GitListFiles(dir string) ([]string, error) {
+	output, err := runGit(dir, "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("列出 Git 文件失败: %w", err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob 检查路径是否匹配 glob 模式
+// 模式和路径都使用 / 分隔，以项目根目录为起点；** 匹配任意层级的目录，
+// 例如 **/*.map 匹配任意目录下的 .map 文件，public/** 匹配 public 下的所有文件
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(splitPath(pattern), splitPath(name))
+}
+
+// ValidateGlob 检查 glob 模式语法是否正确
+func ValidateGlob(pattern string) error {
+	for _, segment := range splitPath(pattern) {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GlobBase 返回 glob 模式中不含通配符的前缀目录
+func GlobBase(pattern string) string {
+	var base []string
+	for _, segment := range splitPath(pattern) {
+		if strings.ContainsAny(segment, "*?[") {
+			break
+		}
+		base = append(base, segment)
+	}
+	return strings.Join(base, "/")
+}
+
+// matchSegments 逐段匹配
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// splitPath 按 / 拆分路径，忽略开头的 ./ 和空段
+func splitPath(p string) []string {
+	p = strings.TrimPrefix(p, "./")
+	var segments []string
+	for _, segment := range strings.Split(p, "/") {
+		if segment != "" && segment != "." {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
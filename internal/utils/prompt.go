@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// IsTerminal 检查文件是否为终端，用于判断能否交互式提问
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Prompter 交互式提问，直接回车时使用默认值
+type Prompter struct {
+	reader *bufio.Reader
+	out    io.Writer
+}
+
+// NewPrompter 创建 Prompter，从 in 读取回答，问题写入 out
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{reader: bufio.NewReader(in), out: out}
+}
+
+// Ask 提问并返回回答，输入结束 (EOF) 时返回默认值
+func (p *Prompter) Ask(question, defaultValue string) string {
+	if defaultValue != "" {
+		fmt.Fprintf(p.out, "❓ %s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(p.out, "❓ %s: ", question)
+	}
+
+	line, err := p.reader.ReadString('\n')
+	answer := strings.TrimSpace(line)
+	if err != nil && answer == "" {
+		fmt.Fprintln(p.out)
+		return defaultValue
+	}
+	if answer == "" {
+		return defaultValue
+	}
+	return answer
+}
+
+// Select 从选项中选择一项，可以输入选项或序号，输入无效时重新提问
+func (p *Prompter) Select(question string, options []string, defaultValue string) string {
+	question = fmt.Sprintf("%s (%s)", question, strings.Join(options, "/"))
+	for {
+		answer := p.Ask(question, defaultValue)
+		for i, option := range options {
+			if strings.EqualFold(answer, option) || answer == fmt.Sprint(i+1) {
+				return option
+			}
+		}
+		if answer == defaultValue {
+			return defaultValue
+		}
+		fmt.Fprintf(p.out, "   请输入 %s 之一\n", strings.Join(options, "、"))
+	}
+}
+
+// Confirm 提问是或否
+func (p *Prompter) Confirm(question string, defaultValue bool) bool {
+	hint := "y/N"
+	if defaultValue {
+		hint = "Y/n"
+	}
+	for {
+		answer := strings.ToLower(p.Ask(fmt.Sprintf("%s (%s)", question, hint), ""))
+		switch answer {
+		case "":
+			return defaultValue
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// redactedText 替换敏感值的文本
+const redactedText = "******"
+
+// flushTimeout 退出前等待输出转发完成的最长时间，后台子进程可能一直持有管道
+const flushTimeout = 2 * time.Second
+
+// redaction 已登记的敏感值及输出过滤状态
+var redaction struct {
+	sync.Mutex
+	secrets [][]byte
+	streams []*redactStream
+}
+
+// redactStream 过滤标准输出或标准错误的管道
+type redactStream struct {
+	target *os.File // 原始的标准输出或标准错误
+	writer *os.File // 替换后的 os.Stdout 或 os.Stderr
+	done   chan struct{}
+}
+
+// RegisterSecret 登记敏感值
+//
+// 登记后本进程以及此后启动的子进程写入标准输出、标准错误的内容都会经过过滤，
+// 敏感值替换为 ******，包括 --verbose 输出、命令输出和错误信息。
+// 过短的值也会替换，输出中相同的普通文本会一并被替换，调用方应提示用户。
+func RegisterSecret(value string) {
+	if value == "" {
+		return
+	}
+
+	redaction.Lock()
+	defer redaction.Unlock()
+
+	for _, secret := range redaction.secrets {
+		if string(secret) == value {
+			return
+		}
+	}
+	redaction.secrets = append(redaction.secrets, []byte(value))
+	// 较长的值优先替换，避免只替换其中一部分
+	sort.Slice(redaction.secrets, func(i, j int) bool {
+		return len(redaction.secrets[i]) > len(redaction.secrets[j])
+	})
+
+	if redaction.streams == nil {
+		installRedaction()
+	}
+}
+
+// Redact 替换字符串中已登记的敏感值
+func Redact(s string) string {
+	redaction.Lock()
+	defer redaction.Unlock()
+	return string(redactBytes([]byte(s)))
+}
+
+// FlushOutput 转发剩余的输出并恢复标准输出、标准错误，程序退出前调用
+func FlushOutput() {
+	redaction.Lock()
+	streams := redaction.streams
+	redaction.streams = nil
+	redaction.Unlock()
+
+	for _, stream := range streams {
+		stream.writer.Close()
+	}
+	timeout := time.After(flushTimeout)
+	for _, stream := range streams {
+		select {
+		case <-stream.done:
+		case <-timeout:
+		}
+	}
+	if len(streams) == 2 {
+		os.Stdout, os.Stderr = streams[0].target, streams[1].target
+	}
+}
+
+// installRedaction 将 os.Stdout、os.Stderr 替换为过滤敏感值的管道，调用时需持有锁
+func installRedaction() {
+	stdout, err := newRedactStream(os.Stdout)
+	if err != nil {
+		return
+	}
+	stderr, err := newRedactStream(os.Stderr)
+	if err != nil {
+		stdout.writer.Close()
+		return
+	}
+
+	redaction.streams = []*redactStream{stdout, stderr}
+	os.Stdout, os.Stderr = stdout.writer, stderr.writer
+}
+
+// newRedactStream 创建管道并在后台转发过滤后的内容
+func newRedactStream(target *os.File) (*redactStream, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &redactStream{target: target, writer: writer, done: make(chan struct{})}
+	go stream.forward(reader)
+	return stream, nil
+}
+
+// forward 转发管道内容，末尾可能是敏感值开头的部分暂不输出，等待后续内容
+func (s *redactStream) forward(reader *os.File) {
+	defer close(s.done)
+	defer reader.Close()
+
+	buf := make([]byte, 32*1024)
+	var pending []byte
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			redaction.Lock()
+			pending = redactBytes(append(pending, buf[:n]...))
+			keep := partialSecret(pending)
+			redaction.Unlock()
+
+			s.target.Write(pending[:len(pending)-keep])
+			pending = append([]byte(nil), pending[len(pending)-keep:]...)
+		}
+		if err != nil {
+			break
+		}
+	}
+	s.target.Write(pending)
+}
+
+// redactBytes 替换已登记的敏感值，调用时需持有锁
+func redactBytes(data []byte) []byte {
+	for _, secret := range redaction.secrets {
+		if bytes.Contains(data, secret) {
+			data = bytes.ReplaceAll(data, secret, []byte(redactedText))
+		}
+	}
+	return data
+}
+
+// partialSecret 返回末尾与某个敏感值开头相同的最长长度，调用时需持有锁
+func partialSecret(data []byte) int {
+	longest := 0
+	for _, secret := range redaction.secrets {
+		for n := len(secret) - 1; n > longest; n-- {
+			if n <= len(data) && bytes.HasSuffix(data, secret[:n]) {
+				longest = n
+				break
+			}
+		}
+	}
+	return longest
+}
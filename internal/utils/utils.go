@@ -28,6 +28,29 @@ func EnsureDir(path string) error {
 	return nil
 }
 
+// CopyFile 复制文件，保留文件权限
+func CopyFile(src, dst string) error {
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	info, err := sourceFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	destFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	_, err = destFile.ReadFrom(sourceFile)
+	return err
+}
+
 // GetProjectName 从路径获取项目名称
 func GetProjectName(projectPath string) string {
 	if projectPath == "" || projectPath == "." {
@@ -69,10 +92,12 @@ func SanitizeFileName(name string) string {
 	return result
 }
 
+// ProjectTypes 支持的项目类型
+var ProjectTypes = []string{"npm", "maven", "gradle", "go", "python", "docker", "auto"}
+
 // IsValidProjectType 检查项目类型是否有效
 func IsValidProjectType(projectType string) bool {
-	validTypes := []string{"npm", "maven", "gradle", "auto"}
-	for _, t := range validTypes {
+	for _, t := range ProjectTypes {
 		if t == projectType {
 			return true
 		}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"deploy/cmd"
+	"deploy/internal/utils"
 	"os"
 )
 
 func main() {
-	if err := cmd.Execute(); err != nil {
+	err := cmd.Execute()
+	utils.FlushOutput()
+	if err != nil {
 		os.Exit(1)
 	}
 }